@@ -0,0 +1,229 @@
+package gojson
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+// ErrDuplicateKey is returned by Canonicalize for an object with the
+// same key twice, which has no canonical form
+var ErrDuplicateKey = fmt.Errorf("canonicalize: duplicate key")
+
+// Canonicalize returns the json document b in the form RFC 8785, the
+// JSON Canonicalization Scheme, defines so that equal documents are
+// equal byte for byte, e.g. for signing:
+//
+//   - no whitespace
+//   - object members sorted by their keys as utf-16 code units
+//   - numbers as ECMAScript writes them, 1.50 as 1.5 and 1E30 as 1e+30
+//   - strings with only '"', '\\' and control characters escaped
+//
+// Numbers are float64s in JCS. One that is too large for a float64
+// returns an ErrNumberRange error, and precision beyond a float64 is
+// lost.
+func Canonicalize(b []byte) ([]byte, error) {
+	if err := ValidateJSON(b); err != nil {
+		return nil, err
+	}
+	_, c := ParseWhitespace(b)
+	return appendCanonical(make([]byte, 0, len(b)), b[c:])
+}
+
+// appendCanonical appends the canonical form of the valid value at the
+// front of v
+func appendCanonical(dst []byte, v Value) ([]byte, error) {
+	switch v.Kind() {
+	case KindObject:
+		unquoted, err := unquotedMembers(v)
+		if err != nil {
+			return dst, err
+		}
+		// sorted by their keys as utf-16
+		type sortable struct {
+			member
+			utf16 []uint16
+		}
+		members := make([]sortable, len(unquoted))
+		for i, m := range unquoted {
+			members[i] = sortable{m, utf16.Encode([]rune(string(m.key)))}
+		}
+
+		sort.Slice(members, func(i, j int) bool {
+			return compareUTF16(members[i].utf16, members[j].utf16) < 0
+		})
+
+		dst = append(dst, '{')
+		for i, m := range members {
+			if i > 0 {
+				if compareUTF16(members[i-1].utf16, m.utf16) == 0 {
+					return dst, fmt.Errorf("%w: %q", ErrDuplicateKey, m.key)
+				}
+				dst = append(dst, ',')
+			}
+			dst = appendCanonicalString(dst, m.key)
+			dst = append(dst, ':')
+			if dst, err = appendCanonical(dst, m.value); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+
+	case KindArray:
+		var err error
+		dst = append(dst, '[')
+		i := 0
+		eachElement(v, func(e Value, c int) {
+			if err != nil {
+				return
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			i++
+			dst, err = appendCanonical(dst, e)
+		})
+		if err != nil {
+			return dst, err
+		}
+		return append(dst, ']'), nil
+
+	case KindString:
+		s, _, err := ParseString(v)
+		if err != nil {
+			return dst, err
+		}
+		u, err := s.Unquote()
+		if err != nil {
+			return dst, err
+		}
+		return appendCanonicalString(dst, u), nil
+
+	case KindNumber:
+		n, _, err := ParseNumber(v)
+		if err != nil {
+			return dst, err
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return dst, err
+		}
+		return appendECMAScriptNumber(dst, f), nil
+	}
+
+	// true, false and null are already canonical
+	switch v[0] {
+	case 't':
+		return append(dst, "true"...), nil
+	case 'f':
+		return append(dst, "false"...), nil
+	}
+	return append(dst, "null"...), nil
+}
+
+func compareUTF16(a, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// appendCanonicalString appends the unquoted string s escaped as
+// RFC 8785 requires, which is as little as json allows
+func appendCanonicalString(dst, s []byte) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i, b := range s {
+		if b >= 0x20 && b != '"' && b != '\\' {
+			continue
+		}
+		dst = append(dst, s[start:i]...)
+		switch b {
+		case '"', '\\':
+			dst = append(dst, '\\', b)
+		case '\b':
+			dst = append(dst, '\\', 'b')
+		case '\f':
+			dst = append(dst, '\\', 'f')
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xf])
+		}
+		start = i + 1
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendECMAScriptNumber appends the finite f the way ECMAScript's
+// Number.prototype.toString writes it: the shortest digits that round
+// trip, with an exponent only below 1e-6 and from 1e21 up
+func appendECMAScriptNumber(dst []byte, f float64) []byte {
+	if f == 0 {
+		return append(dst, '0') // -0 included
+	}
+	if f < 0 {
+		dst = append(dst, '-')
+		f = -f
+	}
+
+	// strconv gives d.ddde±x, split it into digits and n, where the
+	// number is 0.digits × 10^n
+	var buf [32]byte
+	e := strconv.AppendFloat(buf[:0], f, 'e', -1, 64)
+	mantissa, exp := e, 0
+	for i, c := range e {
+		if c == 'e' {
+			mantissa = e[:i]
+			exp, _ = strconv.Atoi(string(e[i+1:]))
+			break
+		}
+	}
+	var d [24]byte
+	digits := append(d[:0], mantissa[0])
+	if len(mantissa) > 2 {
+		digits = append(digits, mantissa[2:]...) // skip the '.'
+	}
+	k, n := len(digits), exp+1
+
+	switch {
+	case k <= n && n <= 21:
+		dst = append(dst, digits...)
+		for i := k; i < n; i++ {
+			dst = append(dst, '0')
+		}
+	case 0 < n && n <= 21:
+		dst = append(dst, digits[:n]...)
+		dst = append(dst, '.')
+		dst = append(dst, digits[n:]...)
+	case -6 < n && n <= 0:
+		dst = append(dst, '0', '.')
+		for i := n; i < 0; i++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, digits...)
+	default:
+		dst = append(dst, digits[0])
+		if k > 1 {
+			dst = append(dst, '.')
+			dst = append(dst, digits[1:]...)
+		}
+		dst = append(dst, 'e')
+		if n-1 >= 0 {
+			dst = append(dst, '+')
+		}
+		dst = strconv.AppendInt(dst, int64(n-1), 10)
+	}
+	return dst
+}
@@ -0,0 +1,154 @@
+package gojson
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  error
+	}{
+		{
+			// RFC 8785 section 3.2.2
+			name: "rfc 8785 example",
+			input: `{
+  "numbers": [333333333.33333329, 1E30, 4.50,
+              2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			expected: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			// RFC 8785 section 3.2.3
+			name: "rfc 8785 sorting",
+			input: `{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`,
+			expected: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"\uFB33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{
+			name:     "nested",
+			input:    ` { "b" : [ { "d" : 1 , "c" : 2 } ] , "a" : { } } `,
+			expected: `{"a":{},"b":[{"c":2,"d":1}]}`,
+		},
+		{
+			name:     "escaped keys sort by value",
+			input:    `{"b": 1, "\u0061": 2}`,
+			expected: `{"a":2,"b":1}`,
+		},
+		{
+			name:     "minimal escapes",
+			input:    `"\u003c\u2028\/\u001f\b\t"`,
+			expected: "\"<\u2028/\\u001f\\b\\t\"",
+		},
+		{
+			name:     "literals",
+			input:    `[true,false,null,-0,0.0,100,1e2]`,
+			expected: `[true,false,null,0,0,100,100]`,
+		},
+		{
+			name:    "duplicate key",
+			input:   `{"a": 1, "\u0061": 2}`,
+			wantErr: ErrDuplicateKey,
+		},
+		{
+			name:    "number too large",
+			input:   `[1e400]`,
+			wantErr: ErrNumberRange,
+		},
+		{
+			name:    "invalid",
+			input:   `{"a" 1}`,
+			wantErr: ErrInvalidMemberMissingSep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Canonicalize([]byte(tt.input))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(actual) != tt.expected {
+				t.Errorf("expected\n%s\ngot\n%s", tt.expected, actual)
+			}
+
+			again, err := Canonicalize(actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(actual) {
+				t.Errorf("canonical form isn't stable, got %s", again)
+			}
+		})
+	}
+}
+
+// TestECMAScriptNumber runs the number samples from RFC 8785 appendix B
+func TestECMAScriptNumber(t *testing.T) {
+	tests := []struct {
+		bits     uint64
+		expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			f := math.Float64frombits(tt.bits)
+			if actual := string(appendECMAScriptNumber(nil, f)); actual != tt.expected {
+				t.Errorf("%#016x: expected %s, got %s", tt.bits, tt.expected, actual)
+			}
+
+			// and through Canonicalize from the shortest form Go writes
+			actual, err := Canonicalize(appendFloat(nil, f, 64))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != tt.expected {
+				t.Errorf("Canonicalize: expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+// ansi colors for the changes diff prints
+const (
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+	colorReset  = "\x1b[0m"
+)
+
+// diffCmd prints the changes from one json file to another. Like diff
+// it exits 0 when there are none, 1 when there are and 2 on errors.
+func diffCmd(args []string) int {
+	var opts gojson.DiffOptions
+
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	patch := fs.Bool("patch", false, "print a JSON Patch (RFC 6902) instead")
+	lcs := fs.Bool("lcs", false, "match array elements by longest common subsequence rather than index")
+	fs.StringVar(&opts.Key, "key", "", "match objects in arrays by this member, e.g. _id")
+	color := fs.String("color", "auto", "color the output: auto, always or never")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gj diff [-patch] [-lcs | -key name] [-color when] a.json b.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 || *lcs && opts.Key != "" {
+		fs.Usage()
+		return 2
+	}
+	switch {
+	case *lcs:
+		opts.Arrays = gojson.ArrayMatchLCS
+	case opts.Key != "":
+		opts.Arrays = gojson.ArrayMatchKey
+	}
+
+	var colored bool
+	switch *color {
+	case "always":
+		colored = true
+	case "never":
+	case "auto":
+		colored = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	a, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gj diff: %v\n", err)
+		return 2
+	}
+	b, err := os.ReadFile(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gj diff: %v\n", err)
+		return 2
+	}
+
+	changes, err := opts.Diff(a, b)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gj diff: %v\n", err)
+		return 2
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if *patch {
+		out, err := gojson.JSONPatch(changes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj diff: %v\n", err)
+			return 2
+		}
+		fmt.Fprintf(w, "%s\n", out)
+	} else {
+		printChanges(w, changes, colored)
+	}
+
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// printChanges writes a line for each change, '+' for added, '-' for
+// removed, '~' for replaced and '>' for moved, followed by its path and
+// values. The whole document is written as (root), "/" is the path of
+// the key "".
+func printChanges(w io.Writer, changes []gojson.Change, colored bool) {
+	for _, c := range changes {
+		path := c.Path.String()
+		if path == "" {
+			path = "(root)"
+		}
+
+		var line, color string
+		switch c.Kind {
+		case gojson.ChangeAdded:
+			line, color = fmt.Sprintf("+ %s: %s", path, compact(c.New)), colorGreen
+		case gojson.ChangeRemoved:
+			line, color = fmt.Sprintf("- %s: %s", path, compact(c.Old)), colorRed
+		case gojson.ChangeMoved:
+			line, color = fmt.Sprintf("> %s -> %s: %s", c.From, path, compact(c.Old)), colorCyan
+		default:
+			line, color = fmt.Sprintf("~ %s: %s -> %s", path, compact(c.Old), compact(c.New)), colorYellow
+		}
+
+		if colored {
+			fmt.Fprintf(w, "%s%s%s\n", color, line, colorReset)
+		} else {
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+func TestPrintChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		opts     gojson.DiffOptions
+		colored  bool
+		expected string
+	}{
+		{
+			name:     "no changes",
+			a:        `{"a": 1}`,
+			b:        `{"a":1.0}`,
+			expected: "",
+		},
+		{
+			name:     "changes",
+			a:        `{"a": 1, "b": [1, 2], "c": {"d": null}}`,
+			b:        `{"a": "1", "b": [1], "c": {"d": null, "e": { "f" : [ ] }}}`,
+			expected: "~ /a: 1 -> \"1\"\n- /b/1: 2\n+ /c/e: {\"f\":[]}\n",
+		},
+		{
+			name:     "whole document",
+			a:        `1`,
+			b:        `2`,
+			expected: "~ (root): 1 -> 2\n",
+		},
+		{
+			name:     "empty key",
+			a:        `{"": 1}`,
+			b:        `{"": 2}`,
+			expected: "~ /: 1 -> 2\n",
+		},
+		{
+			name:     "moved",
+			a:        `[{"id": 1}, {"id": 2, "v": 1}]`,
+			b:        `[{"id": 2, "v": 2}, {"id": 1}]`,
+			opts:     gojson.DiffOptions{Arrays: gojson.ArrayMatchKey, Key: "id"},
+			expected: "> /1 -> /0: {\"id\":2,\"v\":1}\n~ /0/v: 1 -> 2\n",
+		},
+		{
+			name:     "colored",
+			a:        `{"a": 1, "b": 2}`,
+			b:        `{"a": 2, "c": 3}`,
+			colored:  true,
+			expected: "\x1b[33m~ /a: 1 -> 2\x1b[0m\n\x1b[31m- /b: 2\x1b[0m\n\x1b[32m+ /c: 3\x1b[0m\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := tt.opts.Diff([]byte(tt.a), []byte(tt.b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			printChanges(&out, changes, tt.colored)
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+// fmtCmd formats the json files named, or stdin if there are none. By
+// default the result is printed, -w writes it back to the file and -l
+// lists the files that aren't formatted, exiting 1 if there are any.
+func fmtCmd(args []string) int {
+	opts := gojson.DefaultFormatOptions
+
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := fs.Bool("w", false, "write the result to the file instead of stdout")
+	list := fs.Bool("l", false, "list files that aren't formatted and exit 1 if there are any")
+	fs.BoolVar(list, "check", false, "same as -l")
+	fs.StringVar(&opts.Indent, "indent", opts.Indent, "indent for each level of nesting")
+	crlf := fs.Bool("crlf", false, `end lines with "\r\n"`)
+	fs.BoolVar(&opts.SpaceAfterColon, "colon-space", opts.SpaceAfterColon, "put a space after each ':'")
+	fs.IntVar(&opts.ShortArrayWidth, "short", opts.ShortArrayWidth, "keep arrays of literals this wide or less on one line, 0 for never")
+	fs.IntVar(&opts.MaxLineWidth, "width", opts.MaxLineWidth, "don't keep short arrays on one line past this width, 0 for no limit")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gj fmt [-w] [-l|--check] [flags] [file.json ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *crlf {
+		opts.Newline = "\r\n"
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "gj fmt: can't use -w with stdin")
+			return 2
+		}
+		in, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj fmt: %v\n", err)
+			return 2
+		}
+		out, err := gojson.Format(in, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj fmt: <stdin>: %v\n", err)
+			return 5
+		}
+		if *list {
+			if !bytes.Equal(in, out) {
+				fmt.Println("<stdin>")
+				return 1
+			}
+			return 0
+		}
+		os.Stdout.Write(out)
+		return 0
+	}
+
+	code := 0
+	for _, name := range files {
+		changed, err := formatFile(os.Stdout, name, opts, *write, *list)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj fmt: %v\n", err)
+			if _, ok := err.(*os.PathError); ok {
+				return 2
+			}
+			return 5
+		}
+		if changed && *list {
+			code = 1
+		}
+	}
+	return code
+}
+
+// formatFile formats the file name, reporting whether that changed it.
+// With list the name is printed to w if it did, with write the file is
+// rewritten, and with neither the result is printed to w.
+func formatFile(w io.Writer, name string, opts gojson.FormatOptions, write, list bool) (bool, error) {
+	in, err := os.ReadFile(name)
+	if err != nil {
+		return false, err
+	}
+	out, err := gojson.Format(in, opts)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", name, err)
+	}
+	changed := !bytes.Equal(in, out)
+
+	if list && changed {
+		fmt.Fprintln(w, name)
+	}
+	if write && changed {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(name, out, fi.Mode().Perm()); err != nil {
+			return false, err
+		}
+	}
+	if !list && !write {
+		w.Write(out)
+	}
+	return changed, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+func TestFormatFile(t *testing.T) {
+	const formatted = "{\n  \"a\": [1, 2],\n  \"b\": null\n}\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		write    bool
+		list     bool
+		expected string // printed
+		file     string // the file afterwards
+		changed  bool
+		wantErr  bool
+	}{
+		{
+			name:     "print",
+			input:    `{"a":[1,2],"b":null}`,
+			expected: formatted,
+			file:     `{"a":[1,2],"b":null}`,
+			changed:  true,
+		},
+		{
+			name:     "write",
+			input:    `{"a":[1,2],"b":null}`,
+			write:    true,
+			expected: "",
+			file:     formatted,
+			changed:  true,
+		},
+		{
+			name:     "list",
+			input:    `{"a":[1,2],"b":null}`,
+			list:     true,
+			expected: "input.json\n",
+			file:     `{"a":[1,2],"b":null}`,
+			changed:  true,
+		},
+		{
+			name:     "list formatted",
+			input:    formatted,
+			list:     true,
+			expected: "",
+			file:     formatted,
+		},
+		{
+			name:     "write formatted",
+			input:    formatted,
+			write:    true,
+			expected: "",
+			file:     formatted,
+		},
+		{
+			name:    "invalid",
+			input:   `{"a":}`,
+			write:   true,
+			file:    `{"a":}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			name := filepath.Join(dir, "input.json")
+			if err := os.WriteFile(name, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			wd, _ := os.Getwd()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			var out bytes.Buffer
+			changed, err := formatFile(&out, "input.json", gojson.DefaultFormatOptions, tt.write, tt.list)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("expected changed %v, got %v", tt.changed, changed)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected output %q, got %q", tt.expected, out.String())
+			}
+			file, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(file) != tt.file {
+				t.Errorf("expected file %q, got %q", tt.file, file)
+			}
+		})
+	}
+}
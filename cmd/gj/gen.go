@@ -0,0 +1,981 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generatedBy starts the first line of every file gen writes, so that
+// they can be left out when the package is loaded again
+const generatedBy = "// Code generated by gj gen"
+
+// genCmd writes UnmarshalGoJSON methods for the struct types of the
+// package in a directory, typically from
+//
+//	//go:generate go run github.com/jimmyjames85/gojson/cmd/gj gen
+func genCmd(args []string) int {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	typeList := fs.String("type", "", "comma separated `types` to generate for, every struct with a json tag by default")
+	output := fs.String("o", "", "the `file` to write, <package>_gojson.go in the package's directory by default")
+	tests := fs.Bool("tests", true, "write a test for each type beside the output, in a _test.go file")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gj gen [-type T,...] [-o file.go] [-tests=false] [dir]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gj gen: %v\n", err)
+		return 2
+	}
+
+	var names []string
+	if *typeList != "" {
+		names = strings.Split(*typeList, ",")
+	}
+	g, err := newGenerator(pkg, names)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gj gen: %v\n", err)
+		return 3
+	}
+
+	src, test, err := g.generate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gj gen: %v\n", err)
+		return 3
+	}
+
+	file := *output
+	if file == "" {
+		file = filepath.Join(dir, pkg.Name()+"_gojson.go")
+	}
+	if err := os.WriteFile(file, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "gj gen: %v\n", err)
+		return 2
+	}
+	if *tests {
+		if err := os.WriteFile(strings.TrimSuffix(file, ".go")+"_test.go", test, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "gj gen: %v\n", err)
+			return 2
+		}
+	}
+	return 0
+}
+
+// loadPackage type checks the package in dir, leaving out tests and
+// what gen wrote before. Errors are ignored as far as they can be, a
+// field whose type couldn't be found is decoded with gojson.Unmarshal.
+func loadPackage(dir string) (*types.Package, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if len(f.Comments) > 0 && strings.HasPrefix(f.Comments[0].List[0].Text, generatedBy) {
+			continue
+		}
+		if len(files) > 0 && f.Name.Name != files[0].Name.Name {
+			continue // e.g. a "package ignore" file behind a build tag
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no go files in %s", dir)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, nil)
+	return pkg, nil
+}
+
+// genField is a struct field as encoding/json sees it
+type genField struct {
+	name   string
+	path   []*types.Var // the field, after the embedded structs leading to it
+	index  []int        // the position of each of path in its struct
+	tagged bool
+	quoted bool
+}
+
+// generator writes the decoders for structs, and the tests for them
+type generator struct {
+	pkg     *types.Package
+	structs []*types.Named
+	gen     map[*types.Named]bool // the structs being generated for
+
+	imports map[string]string // path to name, of what the output uses
+}
+
+// newGenerator finds the structs named, or every struct with a json
+// tag if there are no names
+func newGenerator(pkg *types.Package, names []string) (*generator, error) {
+	g := &generator{pkg: pkg, gen: map[*types.Named]bool{}}
+
+	scope := pkg.Scope()
+	if len(names) == 0 {
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			if named, ok := tn.Type().(*types.Named); ok && hasJSONTag(named) {
+				g.add(named)
+			}
+		}
+	}
+	for _, name := range names {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("%s: no type %s", pkg.Name(), name)
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is an alias", pkg.Name(), name)
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("%s: %s is not a struct", pkg.Name(), name)
+		}
+		g.add(named)
+	}
+	if len(g.structs) == 0 {
+		return nil, fmt.Errorf("%s: no structs to generate for", pkg.Name())
+	}
+	return g, nil
+}
+
+func (g *generator) add(named *types.Named) {
+	if !g.gen[named] {
+		g.gen[named] = true
+		g.structs = append(g.structs, named)
+	}
+}
+
+func hasJSONTag(named *types.Named) bool {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if _, ok := reflect.StructTag(st.Tag(i)).Lookup("json"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// generate returns the formatted decoders and their tests
+func (g *generator) generate() ([]byte, []byte, error) {
+	g.imports = map[string]string{"github.com/jimmyjames85/gojson": "gojson"}
+	var body bytes.Buffer
+	for _, named := range g.structs {
+		g.decoder(&body, named)
+	}
+	src, err := g.file(body.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g.imports = map[string]string{
+		"github.com/jimmyjames85/gojson": "gojson",
+		"fmt":                            "",
+		"reflect":                        "",
+		"testing":                        "",
+	}
+	body.Reset()
+	for _, named := range g.structs {
+		g.test(&body, named)
+	}
+	test, err := g.file(body.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, test, nil
+}
+
+// file puts the header, package clause and imports on body and formats
+// the lot
+func (g *generator) file(body []byte) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s. DO NOT EDIT.\n\npackage %s\n\nimport (\n", generatedBy, g.pkg.Name())
+	// the standard library first
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, path := range std {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString("\n")
+	for _, path := range other {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString(")\n\n")
+	b.Write(body)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
+
+// qualifier names the packages of the types in the output, importing
+// them as it goes
+func (g *generator) qualifier(p *types.Package) string {
+	if p == g.pkg {
+		return ""
+	}
+	g.imports[p.Path()] = p.Name()
+	return p.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// fieldsVar is the name of the map from json name to field number for
+// named
+func fieldsVar(named *types.Named) string {
+	name := named.Obj().Name()
+	return "goJSONFields" + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// namesVar is the name of the json names of named's fields in order,
+// which keys that only match ignoring case are looked up in so that
+// the first field wins as it does with gojson.Unmarshal
+func namesVar(named *types.Named) string {
+	name := named.Obj().Name()
+	return "goJSONNames" + strings.ToUpper(name[:1]) + name[1:]
+}
+
+func (g *generator) decoder(w *bytes.Buffer, named *types.Named) {
+	name := named.Obj().Name()
+	fields := g.fields(named.Underlying().(*types.Struct))
+
+	fmt.Fprintf(w, "var %s = map[string]int{\n", fieldsVar(named))
+	for i, f := range fields {
+		fmt.Fprintf(w, "%q: %d,\n", f.name, i)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "var %s = []string{\n", namesVar(named))
+	for _, f := range fields {
+		fmt.Fprintf(w, "%q,\n", f.name)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, `// UnmarshalGoJSON decodes the json document data into x as
+// gojson.Unmarshal does, without reflection
+func (x *%[1]s) UnmarshalGoJSON(data []byte) error {
+	if err := gojson.ValidateJSON(data); err != nil {
+		return err
+	}
+	_, c := gojson.ParseWhitespace(data)
+	var state gojson.GenState
+	x.decodeGoJSON(data, c, &state)
+	return state.Err
+}
+
+// decodeGoJSON decodes the value at data[c], which is known to be
+// valid, returning where it ends. The first error is kept in state and
+// decoding carries on past it.
+func (x *%[1]s) decodeGoJSON(data []byte, c int, state *gojson.GenState) int {
+	switch gojson.Value(data[c:]).Kind() {
+	case gojson.KindNull:
+		return c + 4
+	case gojson.KindObject:
+	default:
+		state.TypeError(data, c, x)
+		_, n, _ := gojson.ParseValue(data[c:])
+		return c + n
+	}
+
+	outerStruct, outerField := state.Struct, state.Field
+	state.Struct = %[1]q
+
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			state.Struct, state.Field = outerStruct, outerField
+			return c + 1
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+
+		k, _ := key.Unquote()
+		f, ok := %[2]s[string(k)]
+		if !ok {
+			f = -1
+			for i, name := range %[3]s {
+				if strings.EqualFold(name, string(k)) {
+					f = i
+					break
+				}
+			}
+		}
+		if f >= 0 {
+			state.Field = append(outerField[:len(outerField):len(outerField)], %[3]s[f])
+		}
+
+		switch f {
+`, name, fieldsVar(named), namesVar(named))
+	g.imports["strings"] = ""
+
+	for i, f := range fields {
+		fmt.Fprintf(w, "case %d: // %s\n", i, f.name)
+
+		// allocate the embedded structs on the way to the field
+		target := "x"
+		for _, v := range f.path[:len(f.path)-1] {
+			target += "." + v.Name()
+			if p, ok := v.Type().(*types.Pointer); ok {
+				fmt.Fprintf(w, "if %s == nil {\n%s = new(%s)\n}\n", target, target, g.typeString(p.Elem()))
+			}
+		}
+		target += "." + f.path[len(f.path)-1].Name()
+
+		t := f.path[len(f.path)-1].Type()
+		if f.quoted {
+			g.decodeQuoted(w, target, t)
+		} else {
+			g.decode(w, target, t, 0)
+		}
+	}
+
+	fmt.Fprintf(w, `default:
+			_, n, _ = gojson.ParseValue(data[c:])
+			c += n
+		}
+	}
+}
+
+`)
+}
+
+// fields returns the fields of st that json sees, following the same
+// rules as encoding/json for embedded structs
+func (g *generator) fields(st *types.Struct) []genField {
+	type embedded struct {
+		st    *types.Struct
+		path  []*types.Var
+		index []int
+	}
+
+	var fields []genField
+	next := []embedded{{st: st}}
+	visited := map[*types.Struct]bool{}
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		for _, e := range current {
+			if visited[e.st] {
+				continue
+			}
+			visited[e.st] = true
+
+			for i := 0; i < e.st.NumFields(); i++ {
+				v := e.st.Field(i)
+				tag := reflect.StructTag(e.st.Tag(i)).Get("json")
+				if tag == "-" {
+					continue
+				}
+
+				ft := v.Type()
+				if p, ok := ft.(*types.Pointer); ok {
+					ft = p.Elem()
+				}
+				inner, isStruct := ft.Underlying().(*types.Struct)
+				if v.Embedded() {
+					if !v.Exported() && !isStruct {
+						continue
+					}
+				} else if !v.Exported() {
+					continue
+				}
+
+				parts := strings.Split(tag, ",")
+				path := append(append([]*types.Var{}, e.path...), v)
+				index := append(append([]int{}, e.index...), i)
+				if parts[0] == "" && v.Embedded() && isStruct {
+					if _, ok := v.Type().(*types.Pointer); ok && !v.Exported() {
+						continue // can't be allocated, encoding/json fails on these
+					}
+					next = append(next, embedded{st: inner, path: path, index: index})
+					continue
+				}
+
+				f := genField{name: parts[0], path: path, index: index, tagged: parts[0] != ""}
+				if f.name == "" {
+					f.name = v.Name()
+				}
+				for _, opt := range parts[1:] {
+					if b, ok := ft.Underlying().(*types.Basic); ok && opt == "string" {
+						f.quoted = b.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0
+					}
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	// the shallowest field of a name wins, then the tagged one, and if
+	// that leaves more than one they all lose
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.path) != len(b.path) {
+			return len(a.path) < len(b.path)
+		}
+		return a.tagged && !b.tagged
+	})
+	var kept []genField
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		ambiguous := j-i > 1 &&
+			len(fields[i+1].path) == len(fields[i].path) &&
+			fields[i+1].tagged == fields[i].tagged
+		if !ambiguous {
+			kept = append(kept, fields[i])
+		}
+		i = j
+	}
+
+	// back into the order they are declared in
+	sort.Slice(kept, func(i, j int) bool {
+		a, b := kept[i].index, kept[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return kept
+}
+
+// hasMethod reports whether *t has a method called name
+func hasMethod(t types.Type, name string) bool {
+	if _, ok := t.(*types.Pointer); ok {
+		return false
+	}
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return false
+	}
+	return types.NewMethodSet(types.NewPointer(t)).Lookup(nil, name) != nil
+}
+
+// isJSONNumber reports whether t is encoding/json's Number, which is a
+// string that Unmarshal also decodes numbers into
+func isJSONNumber(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "encoding/json" && named.Obj().Name() == "Number"
+}
+
+// skipValue returns the statements that record a type error for the
+// value at data[c], which can't go in target, and move c past it
+func skipValue(target string) string {
+	return "state.TypeError(data, c, &" + target + ")\n_, n, _ := gojson.ParseValue(data[c:])\nc += n\n"
+}
+
+// decode writes the statements that decode the value at data[c] into
+// target, which has type t, and move c past it. depth numbers the
+// variables of nested slices and maps.
+func (g *generator) decode(w *bytes.Buffer, target string, t types.Type, depth int) {
+	if named, ok := t.(*types.Named); ok && g.gen[named] {
+		fmt.Fprintf(w, "c = %s.decodeGoJSON(data, c, state)\n", target)
+		return
+	}
+
+	for _, method := range []string{"UnmarshalGoJSON", "UnmarshalJSON"} {
+		if hasMethod(t, method) {
+			g.decodeRaw(w, target+"."+method+"(v)")
+			return
+		}
+	}
+	if hasMethod(t, "UnmarshalText") {
+		g.decodeText(w, target, target)
+		return
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		fmt.Fprintf(w, `if data[c] == 'n' {
+	%[1]s = nil
+	c += 4
+} else {
+	if %[1]s == nil {
+		%[1]s = new(%[2]s)
+	}
+`, target, g.typeString(u.Elem()))
+		elem := "(*" + target + ")"
+		if named, ok := u.Elem().(*types.Named); (!ok || !g.gen[named]) &&
+			!hasMethod(u.Elem(), "UnmarshalGoJSON") && !hasMethod(u.Elem(), "UnmarshalJSON") && hasMethod(u.Elem(), "UnmarshalText") {
+			// Unmarshal reports the pointer's type rather than the
+			// type the pointer points to
+			g.decodeText(w, elem, target)
+		} else {
+			g.decode(w, elem, u.Elem(), depth)
+		}
+		fmt.Fprintf(w, "}\n")
+		return
+
+	case *types.Basic:
+		if !isJSONNumber(t) && g.decodeBasic(w, target, t, u) {
+			return
+		}
+
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			break // base64
+		}
+		i, e := fmt.Sprintf("i%d", depth), fmt.Sprintf("e%d", depth)
+		fmt.Fprintf(w, `switch gojson.Value(data[c:]).Kind() {
+case gojson.KindNull:
+	%[1]s = nil
+	c += 4
+case gojson.KindArray:
+	%[2]s := 0
+	c++ // consume the '['
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == ']' {
+			c++
+			break
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		// the elements already in the slice are decoded into
+		if %[2]s == len(%[1]s) {
+			if %[2]s < cap(%[1]s) {
+				%[1]s = %[1]s[:%[2]s+1]
+			} else {
+				var %[3]s %[4]s
+				%[1]s = append(%[1]s, %[3]s)
+			}
+		}
+`, target, i, e, g.typeString(u.Elem()))
+		g.decode(w, target+"["+i+"]", u.Elem(), depth+1)
+		fmt.Fprintf(w, `%[2]s++
+	}
+	if %[2]s == 0 {
+		%[1]s = %[3]s{}
+	} else {
+		%[1]s = %[1]s[:%[2]s]
+	}
+default:
+	`+skipValue("%[1]s")+`}
+`, target, i, g.typeString(t))
+		return
+
+	case *types.Map:
+		if b, ok := u.Key().Underlying().(*types.Basic); !ok || b.Kind() != types.String || hasMethod(u.Key(), "UnmarshalText") {
+			break
+		}
+		k, e := fmt.Sprintf("k%d", depth), fmt.Sprintf("e%d", depth)
+		fmt.Fprintf(w, `switch gojson.Value(data[c:]).Kind() {
+case gojson.KindNull:
+	%[1]s = nil
+	c += 4
+case gojson.KindObject:
+	if %[1]s == nil {
+		%[1]s = make(%[2]s)
+	}
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			c++
+			break
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+		%[3]s, _ := key.Unquote()
+
+		var %[4]s %[5]s
+`, target, g.typeString(t), k, e, g.typeString(u.Elem()))
+		g.decode(w, e, u.Elem(), depth+1)
+		fmt.Fprintf(w, `%[1]s[%[2]s(%[3]s)] = %[4]s
+	}
+default:
+	`+skipValue("%[1]s")+`}
+`, target, g.typeString(u.Key()), k, e)
+		return
+	}
+
+	// everything else goes through reflection
+	fmt.Fprintf(w, "c = state.Unmarshal(data, c, &%s)\n", target)
+}
+
+// decodeRaw writes the statements that hand the value at data[c] to
+// call, an expression that decodes v
+func (g *generator) decodeRaw(w *bytes.Buffer, call string) {
+	fmt.Fprintf(w, `{
+	v, n, _ := gojson.ParseValue(data[c:])
+	state.SaveError(%s)
+	c += n
+}
+`, call)
+}
+
+// decodeText writes decode for a type with an UnmarshalText method,
+// which only strings are decoded with. Type errors are reported for
+// errTarget.
+func (g *generator) decodeText(w *bytes.Buffer, target, errTarget string) {
+	fmt.Fprintf(w, `switch gojson.Value(data[c:]).Kind() {
+case gojson.KindNull:
+	c += 4 // null leaves %[1]s as it is
+case gojson.KindString:
+	s, n, _ := gojson.ParseString(data[c:])
+	v, _ := s.Unquote()
+	state.SaveError(%[1]s.UnmarshalText(v))
+	c += n
+default:
+	`+skipValue("%[2]s")+`}
+`, target, errTarget)
+}
+
+// decodeBasic writes decode for strings, numbers and booleans,
+// reporting whether it could
+func (g *generator) decodeBasic(w *bytes.Buffer, target string, t types.Type, b *types.Basic) bool {
+	typ := g.typeString(t)
+
+	var kind, body string
+	switch info := b.Info(); {
+	case info&types.IsString != 0:
+		kind = "KindString"
+		body = fmt.Sprintf(`s, n, _ := gojson.ParseString(data[c:])
+	v, _ := s.Unquote()
+	%s = %s(v)
+	c += n`, target, typ)
+
+	case info&types.IsBoolean != 0:
+		kind = "KindBool"
+		body = fmt.Sprintf(`_, n, _ := gojson.ParseBoolean(data[c:])
+	%s = data[c] == 't'
+	c += n`, target)
+
+	case info&types.IsInteger != 0:
+		conv, bits := "Int64", "int64"
+		if info&types.IsUnsigned != 0 {
+			conv, bits = "Uint64", "uint64"
+		}
+		overflow := ""
+		if b.Kind() != types.Int64 && b.Kind() != types.Uint64 {
+			overflow = fmt.Sprintf(" || %s(%s(v)) != v", bits, typ)
+		}
+		kind = "KindNumber"
+		body = fmt.Sprintf(`num, n, _ := gojson.ParseNumber(data[c:])
+	if v, err := num.%[1]s(); err != nil%[2]s {
+		state.NumberError(data, c, &%[3]s)
+	} else {
+		%[3]s = %[4]s(v)
+	}
+	c += n`, conv, overflow, target, typ)
+
+	case info&types.IsFloat != 0:
+		overflow := ""
+		if b.Kind() == types.Float32 {
+			g.imports["math"] = ""
+			overflow = " || math.Abs(v) > math.MaxFloat32"
+		}
+		kind = "KindNumber"
+		body = fmt.Sprintf(`num, n, _ := gojson.ParseNumber(data[c:])
+	if v, err := num.Float64(); err != nil%[1]s {
+		state.NumberError(data, c, &%[2]s)
+	} else {
+		%[2]s = %[3]s(v)
+	}
+	c += n`, overflow, target, typ)
+
+	default:
+		return false
+	}
+
+	fmt.Fprintf(w, `switch gojson.Value(data[c:]).Kind() {
+case gojson.KindNull:
+	c += 4 // null leaves %[1]s as it is
+case gojson.%[2]s:
+	%[3]s
+default:
+	`+skipValue("%[1]s")+`}
+`, target, kind, body)
+	return true
+}
+
+// decodeQuoted writes decode for a field tagged ",string", whose value
+// is a string holding a literal
+func (g *generator) decodeQuoted(w *bytes.Buffer, target string, t types.Type) {
+	null := "c += 4 // null leaves " + target + " as it is"
+	if _, ok := t.(*types.Pointer); ok {
+		null = target + " = nil\nc += 4"
+	}
+	// a string can only go in a string, and only a string can
+	str := `lit[0] == '"'`
+	if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+		str = `lit[0] != '"'`
+	}
+
+	fmt.Fprintf(w, `switch gojson.Value(data[c:]).Kind() {
+case gojson.KindNull:
+	%[2]s
+case gojson.KindString:
+	s, n, _ := gojson.ParseString(data[c:])
+	q, _ := s.Unquote()
+	if lit, m, err := gojson.ParseValue(q); err != nil || m != len(q) || lit[0] == '{' || lit[0] == '[' || %[3]s {
+		state.QuotedError(data, c, &%[1]s)
+	} else {
+		data, c := q, 0
+`, target, null, str)
+	g.decode(w, target, t, 0)
+	fmt.Fprintf(w, `_ = c
+	}
+	c += n
+default:
+	state.QuotedError(data, c, &%[1]s)
+	_, n, _ := gojson.ParseValue(data[c:])
+	c += n
+}
+`, target)
+}
+
+// test writes a test that the decoder for named agrees with
+// gojson.Unmarshal, on valid values, values of the wrong type and
+// nulls, both into a zero value and into one that has been decoded
+// into already
+func (g *generator) test(w *bytes.Buffer, named *types.Named) {
+	name := named.Obj().Name()
+	n := new(int)
+	valid, _ := g.sample(named, 0, n, sampleValid)
+	short, _ := g.sample(named, 0, n, sampleShort)
+	wrong, _ := g.sample(named, 0, n, sampleWrong)
+
+	var nulls []string
+	for _, f := range g.fields(named.Underlying().(*types.Struct)) {
+		nulls = append(nulls, strconv.Quote(f.name)+": null")
+	}
+
+	fmt.Fprintf(w, `func Test%[1]sUnmarshalGoJSON(t *testing.T) {
+	valid := %[2]s
+	for _, tc := range []struct{ before, data string }{
+		{"", valid},
+		{"", %[3]s},
+		{"", %[4]s},
+		{"", "{}"},
+		{"", "null"},
+		{"", "[]"},
+		{valid, %[5]s},
+		{valid, %[3]s},
+		{valid, %[4]s},
+	} {
+		var expected, actual %[6]s
+		if tc.before != "" {
+			gojson.Unmarshal([]byte(tc.before), &expected)
+			gojson.Unmarshal([]byte(tc.before), &actual)
+		}
+		expectedErr := gojson.Unmarshal([]byte(tc.data), &expected)
+		err := actual.UnmarshalGoJSON([]byte(tc.data))
+
+		if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%%s: expected error %%v: got %%v", tc.data, expectedErr, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%%s: expected %%#v: got %%#v", tc.data, expected, actual)
+		}
+	}
+}
+
+`, strings.ToUpper(name[:1])+name[1:], "`"+valid+"`", "`"+wrong+"`", "`{"+strings.Join(nulls, ", ")+"}`", "`"+short+"`", name)
+}
+
+// what sample makes
+const (
+	sampleValid = iota
+	sampleShort // valid, with one element in each array
+	sampleWrong // with a value of the wrong type in every other field
+)
+
+// sample returns a json value to decode into a t, n numbers the
+// samples so that each is different. It reports false for types that
+// it doesn't know how to make a value for.
+func (g *generator) sample(t types.Type, depth int, n *int, mode int) (string, bool) {
+	*n++
+	if named, ok := t.(*types.Named); ok && g.gen[named] {
+		if depth > 4 {
+			return "null", true // recursive types stop somewhere
+		}
+		var members []string
+		for i, f := range g.fields(named.Underlying().(*types.Struct)) {
+			ft := f.path[len(f.path)-1].Type()
+			v, ok := g.sample(ft, depth+1, n, mode)
+			if mode == sampleWrong && i%2 == 0 {
+				v, ok = g.wrong(ft, f.quoted, v, ok)
+			} else if f.quoted {
+				v = strconv.Quote(v)
+			}
+			if !ok {
+				continue
+			}
+			members = append(members, strconv.Quote(f.name)+": "+v)
+		}
+		return "{" + strings.Join(members, ", ") + "}", true
+	}
+	if hasMethod(t, "UnmarshalGoJSON") || hasMethod(t, "UnmarshalJSON") {
+		return "", false
+	}
+	if hasMethod(t, "UnmarshalText") {
+		return fmt.Sprintf(`"t%d"`, *n), true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return g.sample(u.Elem(), depth, n, mode)
+	case *types.Basic:
+		switch info := u.Info(); {
+		case info&types.IsString != 0:
+			return fmt.Sprintf(`"s%d \"é\n"`, *n), true
+		case info&types.IsBoolean != 0:
+			return "true", true
+		case info&types.IsInteger != 0:
+			return strconv.Itoa(*n % 100), true
+		case info&types.IsFloat != 0:
+			return strconv.Itoa(*n%100) + ".5", true
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return `"aGVsbG8="`, true
+		}
+		a, ok := g.sample(u.Elem(), depth+1, n, mode)
+		if !ok {
+			return "", false
+		}
+		if mode == sampleShort {
+			return "[" + a + "]", true
+		}
+		b, _ := g.sample(u.Elem(), depth+1, n, mode)
+		return "[" + a + ", " + b + "]", true
+	case *types.Map:
+		if b, ok := u.Key().Underlying().(*types.Basic); !ok || b.Kind() != types.String {
+			return "", false
+		}
+		v, ok := g.sample(u.Elem(), depth+1, n, mode)
+		if !ok {
+			return "", false
+		}
+		return `{"k": ` + v + "}", true
+	}
+	return "", false
+}
+
+// wrong returns a json value that can't go in a t, given v, a sample
+// that can. Structs have wrong values in their fields instead, so that
+// the errors are in fields of nested structs too. A field tagged
+// ",string" gets the literal without the string around it.
+func (g *generator) wrong(t types.Type, quoted bool, v string, ok bool) (string, bool) {
+	if named, isNamed := t.(*types.Named); !ok || quoted || isNamed && g.gen[named] {
+		return v, ok
+	}
+	if hasMethod(t, "UnmarshalText") {
+		return "1", true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return g.wrong(u.Elem(), quoted, v, ok)
+	case *types.Basic:
+		switch info := u.Info(); {
+		case info&types.IsString != 0:
+			return "true", true
+		case info&types.IsBoolean != 0:
+			return "0", true
+		case info&types.IsInteger != 0:
+			return "1e40", true // too big
+		case info&types.IsFloat != 0:
+			return `"1.5"`, true
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return "1", true
+		}
+		return "{}", true
+	case *types.Map:
+		return "[]", true
+	}
+	return v, ok
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGen checks the decoders checked in for genexample are what gen
+// writes now. genexample's own tests check the decoders work.
+func TestGen(t *testing.T) {
+	dir := filepath.Join("internal", "genexample")
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := newGenerator(pkg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, test, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for file, expected := range map[string][]byte{
+		"genexample_gojson.go":      src,
+		"genexample_gojson_test.go": test,
+	} {
+		actual, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("%s is out of date, run go generate ./cmd/gj/internal/genexample", file)
+		}
+	}
+}
+
+func TestGenTypes(t *testing.T) {
+	pkg, err := loadPackage(filepath.Join("internal", "genexample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		types    []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "structs with tags", expected: []string{"Contact", "Folded", "Friend", "Name", "Person", "Settings"}},
+		{name: "named", types: []string{"Friend"}, expected: []string{"Friend"}},
+		{name: "missing", types: []string{"Nope"}, wantErr: true},
+		{name: "not a struct", types: []string{"Color"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		g, err := newGenerator(pkg, tc.types)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		var actual []string
+		for _, named := range g.structs {
+			actual = append(actual, named.Obj().Name())
+		}
+		if len(actual) != len(tc.expected) {
+			t.Errorf("%s: expected %v: got %v", tc.name, tc.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != tc.expected[i] {
+				t.Errorf("%s: expected %v: got %v", tc.name, tc.expected, actual)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package genexample holds types shaped like example.json for gj gen
+// to generate decoders for. The generated code is checked in and
+// gen_test.go checks it is up to date.
+package genexample
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+//go:generate go run github.com/jimmyjames85/gojson/cmd/gj gen
+
+type Person struct {
+	ID         string   `json:"_id"`
+	Index      int      `json:"index"`
+	GUID       string   `json:"guid"`
+	IsActive   bool     `json:"isActive"`
+	Balance    string   `json:"balance"`
+	Picture    string   `json:"picture"`
+	Age        uint8    `json:"age"`
+	EyeColor   Color    `json:"eyeColor"`
+	Name       Name     `json:"name"`
+	Company    string   `json:"company"`
+	Email      string   `json:"email,omitempty"`
+	Phone      *string  `json:"phone"`
+	About      string   `json:"about"`
+	Registered string   `json:"registered"`
+	Latitude   float64  `json:"latitude,string"`
+	Longitude  float32  `json:"longitude,string"`
+	Tags       []string `json:"tags"`
+	Range      []int64  `json:"range"`
+	Friends    []Friend `json:"friends"`
+	Greeting   string   `json:"greeting"`
+	Fruit      string   `json:"favoriteFruit"`
+	Ignored    string   `json:"-"`
+
+	Contact
+	Extra map[string][]*Name `json:"extra,omitempty"`
+	Raw   json.RawMessage    `json:"raw,omitempty"`
+	Any   interface{}        `json:"any,omitempty"`
+	Delay time.Duration      `json:"delay,omitempty"`
+
+	address string
+}
+
+type Color string
+
+type Name struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+type Friend struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Contact is embedded in Person, its fields are promoted
+type Contact struct {
+	Address string `json:"address"`
+	Phone   string `json:"phone"` // hidden by Person.Phone
+}
+
+// Folded has two fields named the same ignoring case, a key that
+// matches neither exactly goes to the first
+type Folded struct {
+	Foo string `json:"Foo"`
+	FOO string `json:"FOO"`
+}
+
+// Settings has fields that Unmarshal decodes in ways that are easy to
+// get wrong
+type Settings struct {
+	Mode    Upper    `json:"mode"`
+	Backup  *Upper   `json:"backup"`
+	Retries int      `json:"retries,string"`
+	Ratio   *float64 `json:"ratio,string"`
+	Limits  []int8   `json:"limits"`
+}
+
+// Upper is a string that is upper cased when it is unmarshaled
+type Upper string
+
+func (u *Upper) UnmarshalText(text []byte) error {
+	*u = Upper(bytes.ToUpper(text))
+	return nil
+}
@@ -0,0 +1,86 @@
+package genexample
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+func TestUnmarshalExample(t *testing.T) {
+	example, err := os.ReadFile("../../../../example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var people []Person
+	if err := gojson.Unmarshal(example, &people); err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range people {
+		v, _, err := gojson.Get(example, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual Person
+		if err := actual.UnmarshalGoJSON(v); err != nil {
+			t.Fatal(err)
+		}
+		if actual.Name != expected.Name || len(actual.Friends) != len(expected.Friends) || actual.Latitude != expected.Latitude {
+			t.Errorf("%d: expected %+v: got %+v", i, expected, actual)
+		}
+	}
+	if people[3].Name.First != "Hayden" || people[0].Latitude != -69.918981 {
+		t.Errorf("unexpected %+v", people[0])
+	}
+}
+
+func TestUnmarshalFolded(t *testing.T) {
+	data := []byte(`{"foo": "x"}`)
+	var expected Folded
+	if err := gojson.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if expected.Foo != "x" {
+		t.Fatalf("expected Foo to be set: got %+v", expected)
+	}
+
+	// map iteration order is random, try a few times
+	for i := 0; i < 20; i++ {
+		var actual Folded
+		if err := actual.UnmarshalGoJSON(data); err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Fatalf("expected %+v: got %+v", expected, actual)
+		}
+	}
+}
+
+func benchmarkPerson(b *testing.B, decode func([]byte, *Person) error) {
+	example, err := os.ReadFile("../../../../example.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	v, _, err := gojson.Get(example, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(v)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var p Person
+		if err := decode(v, &p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	benchmarkPerson(b, func(data []byte, p *Person) error { return gojson.Unmarshal(data, p) })
+}
+
+func BenchmarkUnmarshalGoJSON(b *testing.B) {
+	benchmarkPerson(b, func(data []byte, p *Person) error { return p.UnmarshalGoJSON(data) })
+}
@@ -0,0 +1,1430 @@
+// Code generated by gj gen. DO NOT EDIT.
+
+package genexample
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+var goJSONFieldsContact = map[string]int{
+	"address": 0,
+	"phone":   1,
+}
+
+var goJSONNamesContact = []string{
+	"address",
+	"phone",
+}
+
+// UnmarshalGoJSON decodes the json document data into x as
+// gojson.Unmarshal does, without reflection
+func (x *Contact) UnmarshalGoJSON(data []byte) error {
+	if err := gojson.ValidateJSON(data); err != nil {
+		return err
+	}
+	_, c := gojson.ParseWhitespace(data)
+	var state gojson.GenState
+	x.decodeGoJSON(data, c, &state)
+	return state.Err
+}
+
+// decodeGoJSON decodes the value at data[c], which is known to be
+// valid, returning where it ends. The first error is kept in state and
+// decoding carries on past it.
+func (x *Contact) decodeGoJSON(data []byte, c int, state *gojson.GenState) int {
+	switch gojson.Value(data[c:]).Kind() {
+	case gojson.KindNull:
+		return c + 4
+	case gojson.KindObject:
+	default:
+		state.TypeError(data, c, x)
+		_, n, _ := gojson.ParseValue(data[c:])
+		return c + n
+	}
+
+	outerStruct, outerField := state.Struct, state.Field
+	state.Struct = "Contact"
+
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			state.Struct, state.Field = outerStruct, outerField
+			return c + 1
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+
+		k, _ := key.Unquote()
+		f, ok := goJSONFieldsContact[string(k)]
+		if !ok {
+			f = -1
+			for i, name := range goJSONNamesContact {
+				if strings.EqualFold(name, string(k)) {
+					f = i
+					break
+				}
+			}
+		}
+		if f >= 0 {
+			state.Field = append(outerField[:len(outerField):len(outerField)], goJSONNamesContact[f])
+		}
+
+		switch f {
+		case 0: // address
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Address as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Address = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Address)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 1: // phone
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Phone as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Phone = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Phone)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		default:
+			_, n, _ = gojson.ParseValue(data[c:])
+			c += n
+		}
+	}
+}
+
+var goJSONFieldsFolded = map[string]int{
+	"Foo": 0,
+	"FOO": 1,
+}
+
+var goJSONNamesFolded = []string{
+	"Foo",
+	"FOO",
+}
+
+// UnmarshalGoJSON decodes the json document data into x as
+// gojson.Unmarshal does, without reflection
+func (x *Folded) UnmarshalGoJSON(data []byte) error {
+	if err := gojson.ValidateJSON(data); err != nil {
+		return err
+	}
+	_, c := gojson.ParseWhitespace(data)
+	var state gojson.GenState
+	x.decodeGoJSON(data, c, &state)
+	return state.Err
+}
+
+// decodeGoJSON decodes the value at data[c], which is known to be
+// valid, returning where it ends. The first error is kept in state and
+// decoding carries on past it.
+func (x *Folded) decodeGoJSON(data []byte, c int, state *gojson.GenState) int {
+	switch gojson.Value(data[c:]).Kind() {
+	case gojson.KindNull:
+		return c + 4
+	case gojson.KindObject:
+	default:
+		state.TypeError(data, c, x)
+		_, n, _ := gojson.ParseValue(data[c:])
+		return c + n
+	}
+
+	outerStruct, outerField := state.Struct, state.Field
+	state.Struct = "Folded"
+
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			state.Struct, state.Field = outerStruct, outerField
+			return c + 1
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+
+		k, _ := key.Unquote()
+		f, ok := goJSONFieldsFolded[string(k)]
+		if !ok {
+			f = -1
+			for i, name := range goJSONNamesFolded {
+				if strings.EqualFold(name, string(k)) {
+					f = i
+					break
+				}
+			}
+		}
+		if f >= 0 {
+			state.Field = append(outerField[:len(outerField):len(outerField)], goJSONNamesFolded[f])
+		}
+
+		switch f {
+		case 0: // Foo
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Foo as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Foo = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Foo)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 1: // FOO
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.FOO as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.FOO = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.FOO)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		default:
+			_, n, _ = gojson.ParseValue(data[c:])
+			c += n
+		}
+	}
+}
+
+var goJSONFieldsFriend = map[string]int{
+	"id":   0,
+	"name": 1,
+}
+
+var goJSONNamesFriend = []string{
+	"id",
+	"name",
+}
+
+// UnmarshalGoJSON decodes the json document data into x as
+// gojson.Unmarshal does, without reflection
+func (x *Friend) UnmarshalGoJSON(data []byte) error {
+	if err := gojson.ValidateJSON(data); err != nil {
+		return err
+	}
+	_, c := gojson.ParseWhitespace(data)
+	var state gojson.GenState
+	x.decodeGoJSON(data, c, &state)
+	return state.Err
+}
+
+// decodeGoJSON decodes the value at data[c], which is known to be
+// valid, returning where it ends. The first error is kept in state and
+// decoding carries on past it.
+func (x *Friend) decodeGoJSON(data []byte, c int, state *gojson.GenState) int {
+	switch gojson.Value(data[c:]).Kind() {
+	case gojson.KindNull:
+		return c + 4
+	case gojson.KindObject:
+	default:
+		state.TypeError(data, c, x)
+		_, n, _ := gojson.ParseValue(data[c:])
+		return c + n
+	}
+
+	outerStruct, outerField := state.Struct, state.Field
+	state.Struct = "Friend"
+
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			state.Struct, state.Field = outerStruct, outerField
+			return c + 1
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+
+		k, _ := key.Unquote()
+		f, ok := goJSONFieldsFriend[string(k)]
+		if !ok {
+			f = -1
+			for i, name := range goJSONNamesFriend {
+				if strings.EqualFold(name, string(k)) {
+					f = i
+					break
+				}
+			}
+		}
+		if f >= 0 {
+			state.Field = append(outerField[:len(outerField):len(outerField)], goJSONNamesFriend[f])
+		}
+
+		switch f {
+		case 0: // id
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.ID as it is
+			case gojson.KindNumber:
+				num, n, _ := gojson.ParseNumber(data[c:])
+				if v, err := num.Int64(); err != nil || int64(int(v)) != v {
+					state.NumberError(data, c, &x.ID)
+				} else {
+					x.ID = int(v)
+				}
+				c += n
+			default:
+				state.TypeError(data, c, &x.ID)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 1: // name
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Name as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Name = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Name)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		default:
+			_, n, _ = gojson.ParseValue(data[c:])
+			c += n
+		}
+	}
+}
+
+var goJSONFieldsName = map[string]int{
+	"first": 0,
+	"last":  1,
+}
+
+var goJSONNamesName = []string{
+	"first",
+	"last",
+}
+
+// UnmarshalGoJSON decodes the json document data into x as
+// gojson.Unmarshal does, without reflection
+func (x *Name) UnmarshalGoJSON(data []byte) error {
+	if err := gojson.ValidateJSON(data); err != nil {
+		return err
+	}
+	_, c := gojson.ParseWhitespace(data)
+	var state gojson.GenState
+	x.decodeGoJSON(data, c, &state)
+	return state.Err
+}
+
+// decodeGoJSON decodes the value at data[c], which is known to be
+// valid, returning where it ends. The first error is kept in state and
+// decoding carries on past it.
+func (x *Name) decodeGoJSON(data []byte, c int, state *gojson.GenState) int {
+	switch gojson.Value(data[c:]).Kind() {
+	case gojson.KindNull:
+		return c + 4
+	case gojson.KindObject:
+	default:
+		state.TypeError(data, c, x)
+		_, n, _ := gojson.ParseValue(data[c:])
+		return c + n
+	}
+
+	outerStruct, outerField := state.Struct, state.Field
+	state.Struct = "Name"
+
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			state.Struct, state.Field = outerStruct, outerField
+			return c + 1
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+
+		k, _ := key.Unquote()
+		f, ok := goJSONFieldsName[string(k)]
+		if !ok {
+			f = -1
+			for i, name := range goJSONNamesName {
+				if strings.EqualFold(name, string(k)) {
+					f = i
+					break
+				}
+			}
+		}
+		if f >= 0 {
+			state.Field = append(outerField[:len(outerField):len(outerField)], goJSONNamesName[f])
+		}
+
+		switch f {
+		case 0: // first
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.First as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.First = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.First)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 1: // last
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Last as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Last = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Last)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		default:
+			_, n, _ = gojson.ParseValue(data[c:])
+			c += n
+		}
+	}
+}
+
+var goJSONFieldsPerson = map[string]int{
+	"_id":           0,
+	"index":         1,
+	"guid":          2,
+	"isActive":      3,
+	"balance":       4,
+	"picture":       5,
+	"age":           6,
+	"eyeColor":      7,
+	"name":          8,
+	"company":       9,
+	"email":         10,
+	"phone":         11,
+	"about":         12,
+	"registered":    13,
+	"latitude":      14,
+	"longitude":     15,
+	"tags":          16,
+	"range":         17,
+	"friends":       18,
+	"greeting":      19,
+	"favoriteFruit": 20,
+	"address":       21,
+	"extra":         22,
+	"raw":           23,
+	"any":           24,
+	"delay":         25,
+}
+
+var goJSONNamesPerson = []string{
+	"_id",
+	"index",
+	"guid",
+	"isActive",
+	"balance",
+	"picture",
+	"age",
+	"eyeColor",
+	"name",
+	"company",
+	"email",
+	"phone",
+	"about",
+	"registered",
+	"latitude",
+	"longitude",
+	"tags",
+	"range",
+	"friends",
+	"greeting",
+	"favoriteFruit",
+	"address",
+	"extra",
+	"raw",
+	"any",
+	"delay",
+}
+
+// UnmarshalGoJSON decodes the json document data into x as
+// gojson.Unmarshal does, without reflection
+func (x *Person) UnmarshalGoJSON(data []byte) error {
+	if err := gojson.ValidateJSON(data); err != nil {
+		return err
+	}
+	_, c := gojson.ParseWhitespace(data)
+	var state gojson.GenState
+	x.decodeGoJSON(data, c, &state)
+	return state.Err
+}
+
+// decodeGoJSON decodes the value at data[c], which is known to be
+// valid, returning where it ends. The first error is kept in state and
+// decoding carries on past it.
+func (x *Person) decodeGoJSON(data []byte, c int, state *gojson.GenState) int {
+	switch gojson.Value(data[c:]).Kind() {
+	case gojson.KindNull:
+		return c + 4
+	case gojson.KindObject:
+	default:
+		state.TypeError(data, c, x)
+		_, n, _ := gojson.ParseValue(data[c:])
+		return c + n
+	}
+
+	outerStruct, outerField := state.Struct, state.Field
+	state.Struct = "Person"
+
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			state.Struct, state.Field = outerStruct, outerField
+			return c + 1
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+
+		k, _ := key.Unquote()
+		f, ok := goJSONFieldsPerson[string(k)]
+		if !ok {
+			f = -1
+			for i, name := range goJSONNamesPerson {
+				if strings.EqualFold(name, string(k)) {
+					f = i
+					break
+				}
+			}
+		}
+		if f >= 0 {
+			state.Field = append(outerField[:len(outerField):len(outerField)], goJSONNamesPerson[f])
+		}
+
+		switch f {
+		case 0: // _id
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.ID as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.ID = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.ID)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 1: // index
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Index as it is
+			case gojson.KindNumber:
+				num, n, _ := gojson.ParseNumber(data[c:])
+				if v, err := num.Int64(); err != nil || int64(int(v)) != v {
+					state.NumberError(data, c, &x.Index)
+				} else {
+					x.Index = int(v)
+				}
+				c += n
+			default:
+				state.TypeError(data, c, &x.Index)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 2: // guid
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.GUID as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.GUID = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.GUID)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 3: // isActive
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.IsActive as it is
+			case gojson.KindBool:
+				_, n, _ := gojson.ParseBoolean(data[c:])
+				x.IsActive = data[c] == 't'
+				c += n
+			default:
+				state.TypeError(data, c, &x.IsActive)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 4: // balance
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Balance as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Balance = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Balance)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 5: // picture
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Picture as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Picture = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Picture)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 6: // age
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Age as it is
+			case gojson.KindNumber:
+				num, n, _ := gojson.ParseNumber(data[c:])
+				if v, err := num.Uint64(); err != nil || uint64(uint8(v)) != v {
+					state.NumberError(data, c, &x.Age)
+				} else {
+					x.Age = uint8(v)
+				}
+				c += n
+			default:
+				state.TypeError(data, c, &x.Age)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 7: // eyeColor
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.EyeColor as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.EyeColor = Color(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.EyeColor)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 8: // name
+			c = x.Name.decodeGoJSON(data, c, state)
+		case 9: // company
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Company as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Company = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Company)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 10: // email
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Email as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Email = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Email)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 11: // phone
+			if data[c] == 'n' {
+				x.Phone = nil
+				c += 4
+			} else {
+				if x.Phone == nil {
+					x.Phone = new(string)
+				}
+				switch gojson.Value(data[c:]).Kind() {
+				case gojson.KindNull:
+					c += 4 // null leaves (*x.Phone) as it is
+				case gojson.KindString:
+					s, n, _ := gojson.ParseString(data[c:])
+					v, _ := s.Unquote()
+					(*x.Phone) = string(v)
+					c += n
+				default:
+					state.TypeError(data, c, &(*x.Phone))
+					_, n, _ := gojson.ParseValue(data[c:])
+					c += n
+				}
+			}
+		case 12: // about
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.About as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.About = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.About)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 13: // registered
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Registered as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Registered = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Registered)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 14: // latitude
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Latitude as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				q, _ := s.Unquote()
+				if lit, m, err := gojson.ParseValue(q); err != nil || m != len(q) || lit[0] == '{' || lit[0] == '[' || lit[0] == '"' {
+					state.QuotedError(data, c, &x.Latitude)
+				} else {
+					data, c := q, 0
+					switch gojson.Value(data[c:]).Kind() {
+					case gojson.KindNull:
+						c += 4 // null leaves x.Latitude as it is
+					case gojson.KindNumber:
+						num, n, _ := gojson.ParseNumber(data[c:])
+						if v, err := num.Float64(); err != nil {
+							state.NumberError(data, c, &x.Latitude)
+						} else {
+							x.Latitude = float64(v)
+						}
+						c += n
+					default:
+						state.TypeError(data, c, &x.Latitude)
+						_, n, _ := gojson.ParseValue(data[c:])
+						c += n
+					}
+					_ = c
+				}
+				c += n
+			default:
+				state.QuotedError(data, c, &x.Latitude)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 15: // longitude
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Longitude as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				q, _ := s.Unquote()
+				if lit, m, err := gojson.ParseValue(q); err != nil || m != len(q) || lit[0] == '{' || lit[0] == '[' || lit[0] == '"' {
+					state.QuotedError(data, c, &x.Longitude)
+				} else {
+					data, c := q, 0
+					switch gojson.Value(data[c:]).Kind() {
+					case gojson.KindNull:
+						c += 4 // null leaves x.Longitude as it is
+					case gojson.KindNumber:
+						num, n, _ := gojson.ParseNumber(data[c:])
+						if v, err := num.Float64(); err != nil || math.Abs(v) > math.MaxFloat32 {
+							state.NumberError(data, c, &x.Longitude)
+						} else {
+							x.Longitude = float32(v)
+						}
+						c += n
+					default:
+						state.TypeError(data, c, &x.Longitude)
+						_, n, _ := gojson.ParseValue(data[c:])
+						c += n
+					}
+					_ = c
+				}
+				c += n
+			default:
+				state.QuotedError(data, c, &x.Longitude)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 16: // tags
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				x.Tags = nil
+				c += 4
+			case gojson.KindArray:
+				i0 := 0
+				c++ // consume the '['
+				for {
+					_, n := gojson.ParseWhitespace(data[c:])
+					c += n
+					if data[c] == ']' {
+						c++
+						break
+					}
+					if data[c] == ',' {
+						c++
+						_, n = gojson.ParseWhitespace(data[c:])
+						c += n
+					}
+
+					// the elements already in the slice are decoded into
+					if i0 == len(x.Tags) {
+						if i0 < cap(x.Tags) {
+							x.Tags = x.Tags[:i0+1]
+						} else {
+							var e0 string
+							x.Tags = append(x.Tags, e0)
+						}
+					}
+					switch gojson.Value(data[c:]).Kind() {
+					case gojson.KindNull:
+						c += 4 // null leaves x.Tags[i0] as it is
+					case gojson.KindString:
+						s, n, _ := gojson.ParseString(data[c:])
+						v, _ := s.Unquote()
+						x.Tags[i0] = string(v)
+						c += n
+					default:
+						state.TypeError(data, c, &x.Tags[i0])
+						_, n, _ := gojson.ParseValue(data[c:])
+						c += n
+					}
+					i0++
+				}
+				if i0 == 0 {
+					x.Tags = []string{}
+				} else {
+					x.Tags = x.Tags[:i0]
+				}
+			default:
+				state.TypeError(data, c, &x.Tags)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 17: // range
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				x.Range = nil
+				c += 4
+			case gojson.KindArray:
+				i0 := 0
+				c++ // consume the '['
+				for {
+					_, n := gojson.ParseWhitespace(data[c:])
+					c += n
+					if data[c] == ']' {
+						c++
+						break
+					}
+					if data[c] == ',' {
+						c++
+						_, n = gojson.ParseWhitespace(data[c:])
+						c += n
+					}
+
+					// the elements already in the slice are decoded into
+					if i0 == len(x.Range) {
+						if i0 < cap(x.Range) {
+							x.Range = x.Range[:i0+1]
+						} else {
+							var e0 int64
+							x.Range = append(x.Range, e0)
+						}
+					}
+					switch gojson.Value(data[c:]).Kind() {
+					case gojson.KindNull:
+						c += 4 // null leaves x.Range[i0] as it is
+					case gojson.KindNumber:
+						num, n, _ := gojson.ParseNumber(data[c:])
+						if v, err := num.Int64(); err != nil {
+							state.NumberError(data, c, &x.Range[i0])
+						} else {
+							x.Range[i0] = int64(v)
+						}
+						c += n
+					default:
+						state.TypeError(data, c, &x.Range[i0])
+						_, n, _ := gojson.ParseValue(data[c:])
+						c += n
+					}
+					i0++
+				}
+				if i0 == 0 {
+					x.Range = []int64{}
+				} else {
+					x.Range = x.Range[:i0]
+				}
+			default:
+				state.TypeError(data, c, &x.Range)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 18: // friends
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				x.Friends = nil
+				c += 4
+			case gojson.KindArray:
+				i0 := 0
+				c++ // consume the '['
+				for {
+					_, n := gojson.ParseWhitespace(data[c:])
+					c += n
+					if data[c] == ']' {
+						c++
+						break
+					}
+					if data[c] == ',' {
+						c++
+						_, n = gojson.ParseWhitespace(data[c:])
+						c += n
+					}
+
+					// the elements already in the slice are decoded into
+					if i0 == len(x.Friends) {
+						if i0 < cap(x.Friends) {
+							x.Friends = x.Friends[:i0+1]
+						} else {
+							var e0 Friend
+							x.Friends = append(x.Friends, e0)
+						}
+					}
+					c = x.Friends[i0].decodeGoJSON(data, c, state)
+					i0++
+				}
+				if i0 == 0 {
+					x.Friends = []Friend{}
+				} else {
+					x.Friends = x.Friends[:i0]
+				}
+			default:
+				state.TypeError(data, c, &x.Friends)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 19: // greeting
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Greeting as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Greeting = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Greeting)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 20: // favoriteFruit
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Fruit as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Fruit = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Fruit)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 21: // address
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Contact.Address as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				x.Contact.Address = string(v)
+				c += n
+			default:
+				state.TypeError(data, c, &x.Contact.Address)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 22: // extra
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				x.Extra = nil
+				c += 4
+			case gojson.KindObject:
+				if x.Extra == nil {
+					x.Extra = make(map[string][]*Name)
+				}
+				c++ // consume the '{'
+				for {
+					_, n := gojson.ParseWhitespace(data[c:])
+					c += n
+					if data[c] == '}' {
+						c++
+						break
+					}
+					if data[c] == ',' {
+						c++
+						_, n = gojson.ParseWhitespace(data[c:])
+						c += n
+					}
+
+					key, n, _ := gojson.ParseString(data[c:])
+					c += n
+					_, n = gojson.ParseWhitespace(data[c:])
+					c += n + 1 // the ':'
+					_, n = gojson.ParseWhitespace(data[c:])
+					c += n
+					k0, _ := key.Unquote()
+
+					var e0 []*Name
+					switch gojson.Value(data[c:]).Kind() {
+					case gojson.KindNull:
+						e0 = nil
+						c += 4
+					case gojson.KindArray:
+						i1 := 0
+						c++ // consume the '['
+						for {
+							_, n := gojson.ParseWhitespace(data[c:])
+							c += n
+							if data[c] == ']' {
+								c++
+								break
+							}
+							if data[c] == ',' {
+								c++
+								_, n = gojson.ParseWhitespace(data[c:])
+								c += n
+							}
+
+							// the elements already in the slice are decoded into
+							if i1 == len(e0) {
+								if i1 < cap(e0) {
+									e0 = e0[:i1+1]
+								} else {
+									var e1 *Name
+									e0 = append(e0, e1)
+								}
+							}
+							if data[c] == 'n' {
+								e0[i1] = nil
+								c += 4
+							} else {
+								if e0[i1] == nil {
+									e0[i1] = new(Name)
+								}
+								c = (*e0[i1]).decodeGoJSON(data, c, state)
+							}
+							i1++
+						}
+						if i1 == 0 {
+							e0 = []*Name{}
+						} else {
+							e0 = e0[:i1]
+						}
+					default:
+						state.TypeError(data, c, &e0)
+						_, n, _ := gojson.ParseValue(data[c:])
+						c += n
+					}
+					x.Extra[string(k0)] = e0
+				}
+			default:
+				state.TypeError(data, c, &x.Extra)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 23: // raw
+			{
+				v, n, _ := gojson.ParseValue(data[c:])
+				state.SaveError(x.Raw.UnmarshalJSON(v))
+				c += n
+			}
+		case 24: // any
+			c = state.Unmarshal(data, c, &x.Any)
+		case 25: // delay
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Delay as it is
+			case gojson.KindNumber:
+				num, n, _ := gojson.ParseNumber(data[c:])
+				if v, err := num.Int64(); err != nil {
+					state.NumberError(data, c, &x.Delay)
+				} else {
+					x.Delay = time.Duration(v)
+				}
+				c += n
+			default:
+				state.TypeError(data, c, &x.Delay)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		default:
+			_, n, _ = gojson.ParseValue(data[c:])
+			c += n
+		}
+	}
+}
+
+var goJSONFieldsSettings = map[string]int{
+	"mode":    0,
+	"backup":  1,
+	"retries": 2,
+	"ratio":   3,
+	"limits":  4,
+}
+
+var goJSONNamesSettings = []string{
+	"mode",
+	"backup",
+	"retries",
+	"ratio",
+	"limits",
+}
+
+// UnmarshalGoJSON decodes the json document data into x as
+// gojson.Unmarshal does, without reflection
+func (x *Settings) UnmarshalGoJSON(data []byte) error {
+	if err := gojson.ValidateJSON(data); err != nil {
+		return err
+	}
+	_, c := gojson.ParseWhitespace(data)
+	var state gojson.GenState
+	x.decodeGoJSON(data, c, &state)
+	return state.Err
+}
+
+// decodeGoJSON decodes the value at data[c], which is known to be
+// valid, returning where it ends. The first error is kept in state and
+// decoding carries on past it.
+func (x *Settings) decodeGoJSON(data []byte, c int, state *gojson.GenState) int {
+	switch gojson.Value(data[c:]).Kind() {
+	case gojson.KindNull:
+		return c + 4
+	case gojson.KindObject:
+	default:
+		state.TypeError(data, c, x)
+		_, n, _ := gojson.ParseValue(data[c:])
+		return c + n
+	}
+
+	outerStruct, outerField := state.Struct, state.Field
+	state.Struct = "Settings"
+
+	c++ // consume the '{'
+	for {
+		_, n := gojson.ParseWhitespace(data[c:])
+		c += n
+		if data[c] == '}' {
+			state.Struct, state.Field = outerStruct, outerField
+			return c + 1
+		}
+		if data[c] == ',' {
+			c++
+			_, n = gojson.ParseWhitespace(data[c:])
+			c += n
+		}
+
+		key, n, _ := gojson.ParseString(data[c:])
+		c += n
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n + 1 // the ':'
+		_, n = gojson.ParseWhitespace(data[c:])
+		c += n
+
+		k, _ := key.Unquote()
+		f, ok := goJSONFieldsSettings[string(k)]
+		if !ok {
+			f = -1
+			for i, name := range goJSONNamesSettings {
+				if strings.EqualFold(name, string(k)) {
+					f = i
+					break
+				}
+			}
+		}
+		if f >= 0 {
+			state.Field = append(outerField[:len(outerField):len(outerField)], goJSONNamesSettings[f])
+		}
+
+		switch f {
+		case 0: // mode
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Mode as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				v, _ := s.Unquote()
+				state.SaveError(x.Mode.UnmarshalText(v))
+				c += n
+			default:
+				state.TypeError(data, c, &x.Mode)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 1: // backup
+			if data[c] == 'n' {
+				x.Backup = nil
+				c += 4
+			} else {
+				if x.Backup == nil {
+					x.Backup = new(Upper)
+				}
+				switch gojson.Value(data[c:]).Kind() {
+				case gojson.KindNull:
+					c += 4 // null leaves (*x.Backup) as it is
+				case gojson.KindString:
+					s, n, _ := gojson.ParseString(data[c:])
+					v, _ := s.Unquote()
+					state.SaveError((*x.Backup).UnmarshalText(v))
+					c += n
+				default:
+					state.TypeError(data, c, &x.Backup)
+					_, n, _ := gojson.ParseValue(data[c:])
+					c += n
+				}
+			}
+		case 2: // retries
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				c += 4 // null leaves x.Retries as it is
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				q, _ := s.Unquote()
+				if lit, m, err := gojson.ParseValue(q); err != nil || m != len(q) || lit[0] == '{' || lit[0] == '[' || lit[0] == '"' {
+					state.QuotedError(data, c, &x.Retries)
+				} else {
+					data, c := q, 0
+					switch gojson.Value(data[c:]).Kind() {
+					case gojson.KindNull:
+						c += 4 // null leaves x.Retries as it is
+					case gojson.KindNumber:
+						num, n, _ := gojson.ParseNumber(data[c:])
+						if v, err := num.Int64(); err != nil || int64(int(v)) != v {
+							state.NumberError(data, c, &x.Retries)
+						} else {
+							x.Retries = int(v)
+						}
+						c += n
+					default:
+						state.TypeError(data, c, &x.Retries)
+						_, n, _ := gojson.ParseValue(data[c:])
+						c += n
+					}
+					_ = c
+				}
+				c += n
+			default:
+				state.QuotedError(data, c, &x.Retries)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 3: // ratio
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				x.Ratio = nil
+				c += 4
+			case gojson.KindString:
+				s, n, _ := gojson.ParseString(data[c:])
+				q, _ := s.Unquote()
+				if lit, m, err := gojson.ParseValue(q); err != nil || m != len(q) || lit[0] == '{' || lit[0] == '[' || lit[0] == '"' {
+					state.QuotedError(data, c, &x.Ratio)
+				} else {
+					data, c := q, 0
+					if data[c] == 'n' {
+						x.Ratio = nil
+						c += 4
+					} else {
+						if x.Ratio == nil {
+							x.Ratio = new(float64)
+						}
+						switch gojson.Value(data[c:]).Kind() {
+						case gojson.KindNull:
+							c += 4 // null leaves (*x.Ratio) as it is
+						case gojson.KindNumber:
+							num, n, _ := gojson.ParseNumber(data[c:])
+							if v, err := num.Float64(); err != nil {
+								state.NumberError(data, c, &(*x.Ratio))
+							} else {
+								(*x.Ratio) = float64(v)
+							}
+							c += n
+						default:
+							state.TypeError(data, c, &(*x.Ratio))
+							_, n, _ := gojson.ParseValue(data[c:])
+							c += n
+						}
+					}
+					_ = c
+				}
+				c += n
+			default:
+				state.QuotedError(data, c, &x.Ratio)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		case 4: // limits
+			switch gojson.Value(data[c:]).Kind() {
+			case gojson.KindNull:
+				x.Limits = nil
+				c += 4
+			case gojson.KindArray:
+				i0 := 0
+				c++ // consume the '['
+				for {
+					_, n := gojson.ParseWhitespace(data[c:])
+					c += n
+					if data[c] == ']' {
+						c++
+						break
+					}
+					if data[c] == ',' {
+						c++
+						_, n = gojson.ParseWhitespace(data[c:])
+						c += n
+					}
+
+					// the elements already in the slice are decoded into
+					if i0 == len(x.Limits) {
+						if i0 < cap(x.Limits) {
+							x.Limits = x.Limits[:i0+1]
+						} else {
+							var e0 int8
+							x.Limits = append(x.Limits, e0)
+						}
+					}
+					switch gojson.Value(data[c:]).Kind() {
+					case gojson.KindNull:
+						c += 4 // null leaves x.Limits[i0] as it is
+					case gojson.KindNumber:
+						num, n, _ := gojson.ParseNumber(data[c:])
+						if v, err := num.Int64(); err != nil || int64(int8(v)) != v {
+							state.NumberError(data, c, &x.Limits[i0])
+						} else {
+							x.Limits[i0] = int8(v)
+						}
+						c += n
+					default:
+						state.TypeError(data, c, &x.Limits[i0])
+						_, n, _ := gojson.ParseValue(data[c:])
+						c += n
+					}
+					i0++
+				}
+				if i0 == 0 {
+					x.Limits = []int8{}
+				} else {
+					x.Limits = x.Limits[:i0]
+				}
+			default:
+				state.TypeError(data, c, &x.Limits)
+				_, n, _ := gojson.ParseValue(data[c:])
+				c += n
+			}
+		default:
+			_, n, _ = gojson.ParseValue(data[c:])
+			c += n
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Code generated by gj gen. DO NOT EDIT.
+
+package genexample
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+func TestContactUnmarshalGoJSON(t *testing.T) {
+	valid := `{"address": "s2 \"é\n", "phone": "s3 \"é\n"}`
+	for _, tc := range []struct{ before, data string }{
+		{"", valid},
+		{"", `{"address": true, "phone": "s9 \"é\n"}`},
+		{"", `{"address": null, "phone": null}`},
+		{"", "{}"},
+		{"", "null"},
+		{"", "[]"},
+		{valid, `{"address": "s5 \"é\n", "phone": "s6 \"é\n"}`},
+		{valid, `{"address": true, "phone": "s9 \"é\n"}`},
+		{valid, `{"address": null, "phone": null}`},
+	} {
+		var expected, actual Contact
+		if tc.before != "" {
+			gojson.Unmarshal([]byte(tc.before), &expected)
+			gojson.Unmarshal([]byte(tc.before), &actual)
+		}
+		expectedErr := gojson.Unmarshal([]byte(tc.data), &expected)
+		err := actual.UnmarshalGoJSON([]byte(tc.data))
+
+		if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%s: expected error %v: got %v", tc.data, expectedErr, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %#v: got %#v", tc.data, expected, actual)
+		}
+	}
+}
+
+func TestFoldedUnmarshalGoJSON(t *testing.T) {
+	valid := `{"Foo": "s2 \"é\n", "FOO": "s3 \"é\n"}`
+	for _, tc := range []struct{ before, data string }{
+		{"", valid},
+		{"", `{"Foo": true, "FOO": "s9 \"é\n"}`},
+		{"", `{"Foo": null, "FOO": null}`},
+		{"", "{}"},
+		{"", "null"},
+		{"", "[]"},
+		{valid, `{"Foo": "s5 \"é\n", "FOO": "s6 \"é\n"}`},
+		{valid, `{"Foo": true, "FOO": "s9 \"é\n"}`},
+		{valid, `{"Foo": null, "FOO": null}`},
+	} {
+		var expected, actual Folded
+		if tc.before != "" {
+			gojson.Unmarshal([]byte(tc.before), &expected)
+			gojson.Unmarshal([]byte(tc.before), &actual)
+		}
+		expectedErr := gojson.Unmarshal([]byte(tc.data), &expected)
+		err := actual.UnmarshalGoJSON([]byte(tc.data))
+
+		if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%s: expected error %v: got %v", tc.data, expectedErr, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %#v: got %#v", tc.data, expected, actual)
+		}
+	}
+}
+
+func TestFriendUnmarshalGoJSON(t *testing.T) {
+	valid := `{"id": 2, "name": "s3 \"é\n"}`
+	for _, tc := range []struct{ before, data string }{
+		{"", valid},
+		{"", `{"id": 1e40, "name": "s9 \"é\n"}`},
+		{"", `{"id": null, "name": null}`},
+		{"", "{}"},
+		{"", "null"},
+		{"", "[]"},
+		{valid, `{"id": 5, "name": "s6 \"é\n"}`},
+		{valid, `{"id": 1e40, "name": "s9 \"é\n"}`},
+		{valid, `{"id": null, "name": null}`},
+	} {
+		var expected, actual Friend
+		if tc.before != "" {
+			gojson.Unmarshal([]byte(tc.before), &expected)
+			gojson.Unmarshal([]byte(tc.before), &actual)
+		}
+		expectedErr := gojson.Unmarshal([]byte(tc.data), &expected)
+		err := actual.UnmarshalGoJSON([]byte(tc.data))
+
+		if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%s: expected error %v: got %v", tc.data, expectedErr, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %#v: got %#v", tc.data, expected, actual)
+		}
+	}
+}
+
+func TestNameUnmarshalGoJSON(t *testing.T) {
+	valid := `{"first": "s2 \"é\n", "last": "s3 \"é\n"}`
+	for _, tc := range []struct{ before, data string }{
+		{"", valid},
+		{"", `{"first": true, "last": "s9 \"é\n"}`},
+		{"", `{"first": null, "last": null}`},
+		{"", "{}"},
+		{"", "null"},
+		{"", "[]"},
+		{valid, `{"first": "s5 \"é\n", "last": "s6 \"é\n"}`},
+		{valid, `{"first": true, "last": "s9 \"é\n"}`},
+		{valid, `{"first": null, "last": null}`},
+	} {
+		var expected, actual Name
+		if tc.before != "" {
+			gojson.Unmarshal([]byte(tc.before), &expected)
+			gojson.Unmarshal([]byte(tc.before), &actual)
+		}
+		expectedErr := gojson.Unmarshal([]byte(tc.data), &expected)
+		err := actual.UnmarshalGoJSON([]byte(tc.data))
+
+		if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%s: expected error %v: got %v", tc.data, expectedErr, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %#v: got %#v", tc.data, expected, actual)
+		}
+	}
+}
+
+func TestPersonUnmarshalGoJSON(t *testing.T) {
+	valid := `{"_id": "s2 \"é\n", "index": 3, "guid": "s4 \"é\n", "isActive": true, "balance": "s6 \"é\n", "picture": "s7 \"é\n", "age": 8, "eyeColor": "s9 \"é\n", "name": {"first": "s11 \"é\n", "last": "s12 \"é\n"}, "company": "s13 \"é\n", "email": "s14 \"é\n", "phone": "s16 \"é\n", "about": "s17 \"é\n", "registered": "s18 \"é\n", "latitude": "19.5", "longitude": "20.5", "tags": ["s22 \"é\n", "s23 \"é\n"], "range": [25, 26], "friends": [{"id": 29, "name": "s30 \"é\n"}, {"id": 32, "name": "s33 \"é\n"}], "greeting": "s34 \"é\n", "favoriteFruit": "s35 \"é\n", "address": "s36 \"é\n", "extra": {"k": [{"first": "s41 \"é\n", "last": "s42 \"é\n"}, {"first": "s45 \"é\n", "last": "s46 \"é\n"}]}, "delay": 49}`
+	for _, tc := range []struct{ before, data string }{
+		{"", valid},
+		{"", `{"_id": true, "index": 92, "guid": true, "isActive": true, "balance": true, "picture": "s96 \"é\n", "age": 1e40, "eyeColor": "s98 \"é\n", "name": {"first": true, "last": "s101 \"é\n"}, "company": "s102 \"é\n", "email": true, "phone": "s105 \"é\n", "about": true, "registered": "s107 \"é\n", "latitude": 8.5, "longitude": "9.5", "tags": {}, "range": [14, 15], "friends": {}, "greeting": "s123 \"é\n", "favoriteFruit": true, "address": "s125 \"é\n", "extra": [], "delay": 38}`},
+		{"", `{"_id": null, "index": null, "guid": null, "isActive": null, "balance": null, "picture": null, "age": null, "eyeColor": null, "name": null, "company": null, "email": null, "phone": null, "about": null, "registered": null, "latitude": null, "longitude": null, "tags": null, "range": null, "friends": null, "greeting": null, "favoriteFruit": null, "address": null, "extra": null, "raw": null, "any": null, "delay": null}`},
+		{"", "{}"},
+		{"", "null"},
+		{"", "[]"},
+		{valid, `{"_id": "s51 \"é\n", "index": 52, "guid": "s53 \"é\n", "isActive": true, "balance": "s55 \"é\n", "picture": "s56 \"é\n", "age": 57, "eyeColor": "s58 \"é\n", "name": {"first": "s60 \"é\n", "last": "s61 \"é\n"}, "company": "s62 \"é\n", "email": "s63 \"é\n", "phone": "s65 \"é\n", "about": "s66 \"é\n", "registered": "s67 \"é\n", "latitude": "68.5", "longitude": "69.5", "tags": ["s71 \"é\n"], "range": [73], "friends": [{"id": 76, "name": "s77 \"é\n"}], "greeting": "s78 \"é\n", "favoriteFruit": "s79 \"é\n", "address": "s80 \"é\n", "extra": {"k": [{"first": "s85 \"é\n", "last": "s86 \"é\n"}]}, "delay": 89}`},
+		{valid, `{"_id": true, "index": 92, "guid": true, "isActive": true, "balance": true, "picture": "s96 \"é\n", "age": 1e40, "eyeColor": "s98 \"é\n", "name": {"first": true, "last": "s101 \"é\n"}, "company": "s102 \"é\n", "email": true, "phone": "s105 \"é\n", "about": true, "registered": "s107 \"é\n", "latitude": 8.5, "longitude": "9.5", "tags": {}, "range": [14, 15], "friends": {}, "greeting": "s123 \"é\n", "favoriteFruit": true, "address": "s125 \"é\n", "extra": [], "delay": 38}`},
+		{valid, `{"_id": null, "index": null, "guid": null, "isActive": null, "balance": null, "picture": null, "age": null, "eyeColor": null, "name": null, "company": null, "email": null, "phone": null, "about": null, "registered": null, "latitude": null, "longitude": null, "tags": null, "range": null, "friends": null, "greeting": null, "favoriteFruit": null, "address": null, "extra": null, "raw": null, "any": null, "delay": null}`},
+	} {
+		var expected, actual Person
+		if tc.before != "" {
+			gojson.Unmarshal([]byte(tc.before), &expected)
+			gojson.Unmarshal([]byte(tc.before), &actual)
+		}
+		expectedErr := gojson.Unmarshal([]byte(tc.data), &expected)
+		err := actual.UnmarshalGoJSON([]byte(tc.data))
+
+		if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%s: expected error %v: got %v", tc.data, expectedErr, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %#v: got %#v", tc.data, expected, actual)
+		}
+	}
+}
+
+func TestSettingsUnmarshalGoJSON(t *testing.T) {
+	valid := `{"mode": "t2", "backup": "t4", "retries": "5", "ratio": "7.5", "limits": [9, 10]}`
+	for _, tc := range []struct{ before, data string }{
+		{"", valid},
+		{"", `{"mode": 1, "backup": "t23", "retries": 24, "ratio": "26.5", "limits": {}}`},
+		{"", `{"mode": null, "backup": null, "retries": null, "ratio": null, "limits": null}`},
+		{"", "{}"},
+		{"", "null"},
+		{"", "[]"},
+		{valid, `{"mode": "t12", "backup": "t14", "retries": "15", "ratio": "17.5", "limits": [19]}`},
+		{valid, `{"mode": 1, "backup": "t23", "retries": 24, "ratio": "26.5", "limits": {}}`},
+		{valid, `{"mode": null, "backup": null, "retries": null, "ratio": null, "limits": null}`},
+	} {
+		var expected, actual Settings
+		if tc.before != "" {
+			gojson.Unmarshal([]byte(tc.before), &expected)
+			gojson.Unmarshal([]byte(tc.before), &actual)
+		}
+		expectedErr := gojson.Unmarshal([]byte(tc.data), &expected)
+		err := actual.UnmarshalGoJSON([]byte(tc.data))
+
+		if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%s: expected error %v: got %v", tc.data, expectedErr, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %#v: got %#v", tc.data, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,676 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+// A jq subset: paths (.a, .["a"], .[0], .[], ..), pipes, commas,
+// array and object construction, string interpolation, comparisons,
+// and, or, //, and the functions length, keys, map, select, not,
+// empty and type.
+//
+// Every value is a gojson.Value. Values picked out of the input point
+// into it, constructed ones are built compactly.
+
+// filter produces zero or more outputs from its input
+type filter interface {
+	eval(v gojson.Value) ([]gojson.Value, error)
+}
+
+var (
+	jsonTrue  = gojson.Value(gojson.TrueValue)
+	jsonFalse = gojson.Value(gojson.FalseValue)
+	jsonNull  = gojson.Value(gojson.NullValue)
+)
+
+type identity struct{}
+
+func (identity) eval(v gojson.Value) ([]gojson.Value, error) {
+	return []gojson.Value{v}, nil
+}
+
+// recurse is ".."
+type recurse struct{}
+
+func (recurse) eval(v gojson.Value) ([]gojson.Value, error) {
+	n, err := node(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []gojson.Value
+	var walk func(n *gojson.Node)
+	walk = func(n *gojson.Node) {
+		out = append(out, n.Raw)
+		for i := range n.Members {
+			walk(&n.Members[i].Value)
+		}
+		for i := range n.Elements {
+			walk(&n.Elements[i])
+		}
+	}
+	walk(&n)
+	return out, nil
+}
+
+type literal struct{ v gojson.Value }
+
+func (l literal) eval(gojson.Value) ([]gojson.Value, error) {
+	return []gojson.Value{l.v}, nil
+}
+
+// index is target[key], key is run against the same input as target
+type index struct{ target, key filter }
+
+func (f index) eval(v gojson.Value) ([]gojson.Value, error) {
+	targets, err := f.target.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := f.key.eval(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []gojson.Value
+	for _, t := range targets {
+		for _, k := range keys {
+			r, err := lookup(t, k)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// lookup returns t[k], which is null if k isn't there
+func lookup(t, k gojson.Value) (gojson.Value, error) {
+	var path interface{}
+	switch {
+	case t.IsNull():
+		return jsonNull, nil
+	case t.IsObject() && k.IsString():
+		s, err := gojson.GetString(k)
+		if err != nil {
+			return nil, err
+		}
+		path = s
+	case t.IsArray() && k.IsNumber():
+		f, err := gojson.GetFloat(k)
+		if err != nil {
+			return nil, err
+		}
+		i := int(math.Floor(f))
+		if i < 0 {
+			elements, err := t.AsArray()
+			if err != nil {
+				return nil, err
+			}
+			i += len(elements)
+		}
+		path = i
+	default:
+		return nil, fmt.Errorf("cannot index %s with %s", typeName(t), typeName(k))
+	}
+
+	r, _, err := gojson.Get(t, path)
+	if errors.Is(err, gojson.ErrNotFound) {
+		return jsonNull, nil
+	}
+	return r, err
+}
+
+// iterate is target[]
+type iterate struct{ target filter }
+
+func (f iterate) eval(v gojson.Value) ([]gojson.Value, error) {
+	targets, err := f.target.eval(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []gojson.Value
+	for _, t := range targets {
+		if !t.IsObject() && !t.IsArray() {
+			return nil, fmt.Errorf("cannot iterate over %s", typeName(t))
+		}
+		n, err := node(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range n.Members {
+			out = append(out, m.Value.Raw)
+		}
+		for _, e := range n.Elements {
+			out = append(out, e.Raw)
+		}
+	}
+	return out, nil
+}
+
+// optional is "f?", which outputs nothing rather than fail
+type optional struct{ f filter }
+
+func (o optional) eval(v gojson.Value) ([]gojson.Value, error) {
+	out, err := o.f.eval(v)
+	if err != nil {
+		return nil, nil
+	}
+	return out, nil
+}
+
+type pipe struct{ left, right filter }
+
+func (f pipe) eval(v gojson.Value) ([]gojson.Value, error) {
+	left, err := f.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []gojson.Value
+	for _, l := range left {
+		r, err := f.right.eval(l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r...)
+	}
+	return out, nil
+}
+
+type comma struct{ left, right filter }
+
+func (f comma) eval(v gojson.Value) ([]gojson.Value, error) {
+	left, err := f.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	right, err := f.right.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// alternative is "left // right"
+type alternative struct{ left, right filter }
+
+func (f alternative) eval(v gojson.Value) ([]gojson.Value, error) {
+	// an error on the left counts as no output
+	left, err := f.left.eval(v)
+	if err == nil {
+		var out []gojson.Value
+		for _, l := range left {
+			if truthy(l) {
+				out = append(out, l)
+			}
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+	}
+	return f.right.eval(v)
+}
+
+// binary runs op over every pair of outputs of left and right
+type binary struct {
+	op          string
+	left, right filter
+}
+
+func (f binary) eval(v gojson.Value) ([]gojson.Value, error) {
+	right, err := f.right.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	left, err := f.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []gojson.Value
+	for _, r := range right {
+		for _, l := range left {
+			b, err := f.apply(l, r)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, boolean(b))
+		}
+	}
+	return out, nil
+}
+
+func (f binary) apply(l, r gojson.Value) (bool, error) {
+	switch f.op {
+	case "and":
+		return truthy(l) && truthy(r), nil
+	case "or":
+		return truthy(l) || truthy(r), nil
+	}
+
+	c, err := compare(l, r)
+	if err != nil {
+		return false, err
+	}
+	switch f.op {
+	case "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %s", f.op)
+}
+
+// collect is "[f]"
+type collect struct{ f filter }
+
+func (c collect) eval(v gojson.Value) ([]gojson.Value, error) {
+	var out []gojson.Value
+	if c.f != nil {
+		var err error
+		if out, err = c.f.eval(v); err != nil {
+			return nil, err
+		}
+	}
+	return []gojson.Value{array(out)}, nil
+}
+
+// object is "{key: value, ...}", each combination of the outputs of
+// the keys and values makes an object
+type object struct{ keys, values []filter }
+
+func (o object) eval(v gojson.Value) ([]gojson.Value, error) {
+	partial := [][]byte{nil}
+	for i := range o.keys {
+		keys, err := o.keys[i].eval(v)
+		if err != nil {
+			return nil, err
+		}
+		values, err := o.values[i].eval(v)
+		if err != nil {
+			return nil, err
+		}
+
+		var next [][]byte
+		for _, p := range partial {
+			for _, k := range keys {
+				if !k.IsString() {
+					return nil, fmt.Errorf("object keys must be strings, not %s", typeName(k))
+				}
+				for _, val := range values {
+					b := append([]byte{}, p...)
+					if len(b) > 0 {
+						b = append(b, ',')
+					}
+					b = append(b, compact(k)...)
+					b = append(b, ':')
+					b = append(b, compact(val)...)
+					next = append(next, b)
+				}
+			}
+		}
+		partial = next
+	}
+
+	out := make([]gojson.Value, len(partial))
+	for i, p := range partial {
+		out[i] = gojson.Value("{" + string(p) + "}")
+	}
+	return out, nil
+}
+
+// interpolation is a string literal with "\(f)" in it. The outputs of
+// f are inserted as they are if they are strings, or as json if not.
+type interpolation struct {
+	parts []interface{} // string or filter
+}
+
+func (s interpolation) eval(v gojson.Value) ([]gojson.Value, error) {
+	partial := []string{""}
+	for _, p := range s.parts {
+		f, ok := p.(filter)
+		if !ok {
+			for i := range partial {
+				partial[i] += p.(string)
+			}
+			continue
+		}
+
+		values, err := f.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		var next []string
+		for _, prefix := range partial {
+			for _, val := range values {
+				s := string(compact(val))
+				if val.IsString() {
+					if s, err = gojson.GetString(val); err != nil {
+						return nil, err
+					}
+				}
+				next = append(next, prefix+s)
+			}
+		}
+		partial = next
+	}
+
+	out := make([]gojson.Value, len(partial))
+	for i, p := range partial {
+		out[i] = quote(p)
+	}
+	return out, nil
+}
+
+type call struct {
+	name string
+	arg  filter
+}
+
+func (c call) eval(v gojson.Value) ([]gojson.Value, error) {
+	switch c.name {
+	case "empty":
+		return nil, nil
+	case "not":
+		return []gojson.Value{boolean(!truthy(v))}, nil
+	case "type":
+		return []gojson.Value{quote(typeName(v))}, nil
+	case "length":
+		l, err := length(v)
+		if err != nil {
+			return nil, err
+		}
+		return []gojson.Value{l}, nil
+	case "keys":
+		k, err := keys(v)
+		if err != nil {
+			return nil, err
+		}
+		return []gojson.Value{k}, nil
+	case "map":
+		// [.[] | f]
+		return collect{pipe{iterate{identity{}}, c.arg}}.eval(v)
+	case "select":
+		conds, err := c.arg.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []gojson.Value
+		for _, cond := range conds {
+			if truthy(cond) {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%s is not defined", c.name)
+}
+
+// arity is how many arguments each function takes
+var arity = map[string]int{
+	"empty":  0,
+	"not":    0,
+	"type":   0,
+	"length": 0,
+	"keys":   0,
+	"map":    1,
+	"select": 1,
+}
+
+func length(v gojson.Value) (gojson.Value, error) {
+	switch v.Kind() {
+	case gojson.KindNull:
+		return gojson.Value("0"), nil
+	case gojson.KindNumber:
+		// the absolute value
+		return gojson.Value(strings.TrimPrefix(string(v), "-")), nil
+	case gojson.KindString:
+		s, err := gojson.GetString(v)
+		if err != nil {
+			return nil, err
+		}
+		return gojson.Value(strconv.Itoa(utf8.RuneCountInString(s))), nil
+	case gojson.KindArray, gojson.KindObject:
+		n, err := node(v)
+		if err != nil {
+			return nil, err
+		}
+		return gojson.Value(strconv.Itoa(len(n.Members) + len(n.Elements))), nil
+	}
+	return nil, fmt.Errorf("%s has no length", typeName(v))
+}
+
+// keys returns the sorted keys of an object, or the indexes of an array
+func keys(v gojson.Value) (gojson.Value, error) {
+	if !v.IsObject() && !v.IsArray() {
+		return nil, fmt.Errorf("%s has no keys", typeName(v))
+	}
+	n, err := node(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []gojson.Value
+	for i := range n.Elements {
+		out = append(out, gojson.Value(strconv.Itoa(i)))
+	}
+
+	names, err := memberNames(n)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, quote(name))
+	}
+	return array(out), nil
+}
+
+// memberNames returns the decoded keys of the object n
+func memberNames(n gojson.Node) ([]string, error) {
+	names := make([]string, len(n.Members))
+	for i, m := range n.Members {
+		s, err := m.Key.Unquote()
+		if err != nil {
+			return nil, err
+		}
+		names[i] = string(s)
+	}
+	return names, nil
+}
+
+// rank orders values of different types the way jq does
+func rank(v gojson.Value) int {
+	switch {
+	case v.IsNull():
+		return 0
+	case string(v) == "false":
+		return 1
+	case string(v) == "true":
+		return 2
+	case v.IsNumber():
+		return 3
+	case v.IsString():
+		return 4
+	case v.IsArray():
+		return 5
+	}
+	return 6
+}
+
+// compare orders a and b: null < false < true < numbers < strings <
+// arrays < objects. Arrays compare element by element. Objects compare
+// their sorted keys and then their values key by key.
+func compare(a, b gojson.Value) (int, error) {
+	if ra, rb := rank(a), rank(b); ra != rb || ra < 3 {
+		return ra - rb, nil
+	}
+
+	switch a.Kind() {
+	case gojson.KindNumber:
+		x, err := gojson.GetFloat(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := gojson.GetFloat(b)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	case gojson.KindString:
+		x, err := gojson.GetString(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := gojson.GetString(b)
+		if err != nil {
+			return 0, err
+		}
+		return strings.Compare(x, y), nil
+	case gojson.KindArray:
+		x, err := a.AsArray()
+		if err != nil {
+			return 0, err
+		}
+		y, err := b.AsArray()
+		if err != nil {
+			return 0, err
+		}
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c, err := compare(x[i], y[i]); c != 0 || err != nil {
+				return c, err
+			}
+		}
+		return len(x) - len(y), nil
+	}
+
+	// objects
+	ka, err := keys(a)
+	if err != nil {
+		return 0, err
+	}
+	kb, err := keys(b)
+	if err != nil {
+		return 0, err
+	}
+	if c, err := compare(ka, kb); c != 0 || err != nil {
+		return c, err
+	}
+	names, err := ka.AsArray()
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		x, err := lookup(a, name)
+		if err != nil {
+			return 0, err
+		}
+		y, err := lookup(b, name)
+		if err != nil {
+			return 0, err
+		}
+		if c, err := compare(x, y); c != 0 || err != nil {
+			return c, err
+		}
+	}
+	return 0, nil
+}
+
+func truthy(v gojson.Value) bool {
+	return !v.IsNull() && string(v) != "false"
+}
+
+func boolean(b bool) gojson.Value {
+	if b {
+		return jsonTrue
+	}
+	return jsonFalse
+}
+
+func typeName(v gojson.Value) string {
+	switch v.Kind() {
+	case gojson.KindObject:
+		return "object"
+	case gojson.KindArray:
+		return "array"
+	case gojson.KindString:
+		return "string"
+	case gojson.KindNumber:
+		return "number"
+	case gojson.KindBool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func node(v gojson.Value) (gojson.Node, error) {
+	n, _, err := gojson.ParseNode(v)
+	return n, err
+}
+
+// array builds an array out of values
+func array(values []gojson.Value) gojson.Value {
+	b := []byte{'['}
+	for i, v := range values {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, compact(v)...)
+	}
+	return gojson.Value(append(b, ']'))
+}
+
+// compact returns v without any insignificant whitespace
+func compact(v gojson.Value) []byte {
+	out, _ := gojson.Compact(nil, v)
+	return out
+}
+
+// quote returns s as a json string
+func quote(s string) gojson.Value {
+	const hex = "0123456789abcdef"
+	b := []byte{'"'}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', c)
+		case c == '\n':
+			b = append(b, '\\', 'n')
+		case c == '\t':
+			b = append(b, '\\', 't')
+		case c == '\r':
+			b = append(b, '\\', 'r')
+		case c < 0x20:
+			b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			b = append(b, c)
+		}
+	}
+	return gojson.Value(append(b, '"'))
+}
@@ -0,0 +1,501 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+// parseFilter compiles a jq program
+func parseFilter(src string) (filter, error) {
+	p := jqParser{s: src}
+	p.skipSpace()
+	if p.c == len(p.s) {
+		// an empty program is "."
+		return identity{}, nil
+	}
+
+	f, err := p.pipe()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.c != len(p.s) {
+		return nil, p.fail("end of filter")
+	}
+	return f, nil
+}
+
+// jqParser parses a jq program, c is how much of s has been consumed
+type jqParser struct {
+	s string
+	c int
+}
+
+func (p *jqParser) fail(expected string) error {
+	found := "end of filter"
+	if p.c < len(p.s) {
+		found = fmt.Sprintf("%q", p.s[p.c])
+	}
+	return fmt.Errorf("syntax error at offset %d: expecting %s, found %s", p.c, expected, found)
+}
+
+// peek returns the next byte, or 0 at the end of the program
+func (p *jqParser) peek() byte {
+	if p.c == len(p.s) {
+		return 0
+	}
+	return p.s[p.c]
+}
+
+func (p *jqParser) skipSpace() {
+	for p.c < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.c]) >= 0 {
+		p.c++
+	}
+}
+
+// consume consumes tok if it is next
+func (p *jqParser) consume(tok string) bool {
+	if strings.HasPrefix(p.s[p.c:], tok) {
+		p.c += len(tok)
+		return true
+	}
+	return false
+}
+
+// keyword consumes the word kw if it is next and not the start of a
+// longer identifier
+func (p *jqParser) keyword(kw string) bool {
+	rest := p.s[p.c:]
+	if strings.HasPrefix(rest, kw) && (len(rest) == len(kw) || !isIdent(rest[len(kw)])) {
+		p.c += len(kw)
+		return true
+	}
+	return false
+}
+
+func isIdentStart(b byte) bool {
+	return b == '_' || (b|0x20 >= 'a' && b|0x20 <= 'z')
+}
+
+func isIdent(b byte) bool {
+	return isIdentStart(b) || gojson.IsDigit(b)
+}
+
+func (p *jqParser) ident() string {
+	start := p.c
+	for p.c < len(p.s) && isIdent(p.s[p.c]) {
+		p.c++
+	}
+	return p.s[start:p.c]
+}
+
+// pipe parses "a | b"
+func (p *jqParser) pipe() (filter, error) {
+	f, err := p.comma()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consume("|") {
+		return f, nil
+	}
+	p.skipSpace()
+	right, err := p.pipe()
+	if err != nil {
+		return nil, err
+	}
+	return pipe{f, right}, nil
+}
+
+// comma parses "a, b"
+func (p *jqParser) comma() (filter, error) {
+	f, err := p.alternative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume(",") {
+			return f, nil
+		}
+		p.skipSpace()
+		right, err := p.alternative()
+		if err != nil {
+			return nil, err
+		}
+		f = comma{f, right}
+	}
+}
+
+// alternative parses "a // b"
+func (p *jqParser) alternative() (filter, error) {
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("//") {
+			return f, nil
+		}
+		p.skipSpace()
+		right, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		f = alternative{f, right}
+	}
+}
+
+func (p *jqParser) or() (filter, error) {
+	f, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.keyword("or") {
+			return f, nil
+		}
+		p.skipSpace()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		f = binary{"or", f, right}
+	}
+}
+
+func (p *jqParser) and() (filter, error) {
+	f, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.keyword("and") {
+			return f, nil
+		}
+		p.skipSpace()
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		f = binary{"and", f, right}
+	}
+}
+
+// comparison parses "a == b" and friends, which don't chain
+func (p *jqParser) comparison() (filter, error) {
+	f, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			p.skipSpace()
+			right, err := p.postfix()
+			if err != nil {
+				return nil, err
+			}
+			return binary{op, f, right}, nil
+		}
+	}
+	return f, nil
+}
+
+// postfix parses a term followed by any number of .name, ."name", [],
+// [f] and ?
+func (p *jqParser) postfix() (filter, error) {
+	f, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.consume("?"):
+			f = optional{f}
+		case p.peek() == '[':
+			p.c++
+			p.skipSpace()
+			if p.consume("]") {
+				f = iterate{f}
+				continue
+			}
+			key, err := p.pipe()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if !p.consume("]") {
+				return nil, p.fail("']'")
+			}
+			f = index{f, key}
+		case p.peek() == '.' && p.c+1 < len(p.s) && p.s[p.c+1] == '[':
+			p.c++ // ".[" is the same as "["
+		case p.peek() == '.' && p.c+1 < len(p.s) && (isIdentStart(p.s[p.c+1]) || p.s[p.c+1] == '"'):
+			p.c++
+			key, err := p.fieldName()
+			if err != nil {
+				return nil, err
+			}
+			f = index{f, key}
+		default:
+			return f, nil
+		}
+	}
+}
+
+// fieldName parses the name after a '.', which is an identifier or a
+// string
+func (p *jqParser) fieldName() (filter, error) {
+	if p.peek() == '"' {
+		return p.str()
+	}
+	return literal{quote(p.ident())}, nil
+}
+
+func (p *jqParser) term() (filter, error) {
+	b := p.peek()
+	switch {
+	case b == '.':
+		p.c++
+		switch next := p.peek(); {
+		case next == '.':
+			p.c++
+			return recurse{}, nil
+		case isIdentStart(next) || next == '"':
+			key, err := p.fieldName()
+			if err != nil {
+				return nil, err
+			}
+			return index{identity{}, key}, nil
+		}
+		// a following '[' is picked up by postfix
+		return identity{}, nil
+	case b == '"':
+		return p.str()
+	case b == '-' || gojson.IsDigit(b):
+		rest := []byte(p.s[p.c:])
+		n, c, err := gojson.ParseNumber(rest)
+		if err != nil {
+			return nil, p.fail("number")
+		}
+		p.c += c
+		return literal{gojson.Value(n)}, nil
+	case b == '[':
+		p.c++
+		p.skipSpace()
+		if p.consume("]") {
+			return collect{}, nil
+		}
+		f, err := p.pipe()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume("]") {
+			return nil, p.fail("']'")
+		}
+		return collect{f}, nil
+	case b == '{':
+		return p.object()
+	case b == '(':
+		p.c++
+		p.skipSpace()
+		f, err := p.pipe()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.fail("')'")
+		}
+		return f, nil
+	case isIdentStart(b):
+		return p.call()
+	}
+	return nil, p.fail("filter")
+}
+
+// call parses true, false, null or a function call
+func (p *jqParser) call() (filter, error) {
+	start := p.c
+	name := p.ident()
+	switch name {
+	case "true":
+		return literal{jsonTrue}, nil
+	case "false":
+		return literal{jsonFalse}, nil
+	case "null":
+		return literal{jsonNull}, nil
+	}
+
+	n, ok := arity[name]
+	if !ok {
+		p.c = start
+		return nil, fmt.Errorf("%s is not defined", name)
+	}
+	if n == 0 {
+		return call{name: name}, nil
+	}
+
+	p.skipSpace()
+	if !p.consume("(") {
+		return nil, p.fail("'(' after " + name)
+	}
+	p.skipSpace()
+	arg, err := p.pipe()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consume(")") {
+		return nil, p.fail("')'")
+	}
+	return call{name: name, arg: arg}, nil
+}
+
+// object parses "{a, b: f, "c": g, (h): i}"
+func (p *jqParser) object() (filter, error) {
+	p.c++ // consume the '{'
+	var o object
+	for {
+		p.skipSpace()
+		if len(o.keys) == 0 && p.consume("}") {
+			return o, nil
+		}
+
+		var key filter
+		var err error
+		switch b := p.peek(); {
+		case isIdentStart(b):
+			key = literal{quote(p.ident())}
+		case b == '"':
+			key, err = p.str()
+		case b == '(':
+			p.c++
+			key, err = p.pipe()
+			if err == nil && !p.consume(")") {
+				err = p.fail("')'")
+			}
+		default:
+			err = p.fail("object key")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// {a} is short for {a: .a}
+		value := filter(index{identity{}, key})
+		p.skipSpace()
+		if p.consume(":") {
+			p.skipSpace()
+			if value, err = p.objectValue(); err != nil {
+				return nil, err
+			}
+		}
+		o.keys = append(o.keys, key)
+		o.values = append(o.values, value)
+
+		p.skipSpace()
+		if p.consume("}") {
+			return o, nil
+		}
+		if !p.consume(",") {
+			return nil, p.fail("',' or '}'")
+		}
+	}
+}
+
+// objectValue parses the value of an object member, which may be
+// piped but has to be in parentheses to use ','
+func (p *jqParser) objectValue() (filter, error) {
+	f, err := p.alternative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("|") {
+			return f, nil
+		}
+		p.skipSpace()
+		right, err := p.alternative()
+		if err != nil {
+			return nil, err
+		}
+		f = pipe{f, right}
+	}
+}
+
+// str parses a string literal, which may hold "\(f)"
+func (p *jqParser) str() (filter, error) {
+	start := p.c
+	p.c++ // consume the '"'
+
+	var parts []interface{}
+	var raw []byte // the json string being built since the last \(f)
+	flush := func() error {
+		if len(raw) == 0 {
+			return nil
+		}
+		s, err := gojson.GetString(append(append([]byte{'"'}, raw...), '"'))
+		if err != nil {
+			return err
+		}
+		parts = append(parts, s)
+		raw = raw[:0]
+		return nil
+	}
+
+	for {
+		if p.c == len(p.s) {
+			p.c = start
+			return nil, p.fail("closing '\"'")
+		}
+		b := p.s[p.c]
+		switch {
+		case b == '"':
+			p.c++
+			if err := flush(); err != nil {
+				p.c = start
+				return nil, p.fail("string")
+			}
+			if len(parts) == 0 {
+				return literal{quote("")}, nil
+			}
+			if s, ok := parts[0].(string); ok && len(parts) == 1 {
+				return literal{quote(s)}, nil
+			}
+			return interpolation{parts}, nil
+		case b == '\\' && p.c+1 < len(p.s) && p.s[p.c+1] == '(':
+			if err := flush(); err != nil {
+				p.c = start
+				return nil, p.fail("string")
+			}
+			p.c += 2
+			p.skipSpace()
+			f, err := p.pipe()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if !p.consume(")") {
+				return nil, p.fail("')'")
+			}
+			parts = append(parts, f)
+		case b == '\\' && p.c+1 < len(p.s):
+			raw = append(raw, b, p.s[p.c+1])
+			p.c += 2
+		default:
+			raw = append(raw, b)
+			p.c++
+		}
+	}
+}
@@ -9,7 +9,27 @@ import (
 	"github.com/pkg/profile"
 )
 
+// commands are the gj subcommands, each returns the exit code
+var commands = map[string]func(args []string) int{
+	"diff":  diffCmd,
+	"fmt":   fmtCmd,
+	"gen":   genCmd,
+	"min":   minCmd,
+	"query": queryCmd,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
+	validateExample()
+}
+
+// validateExample validates example.json over and over for profiling
+func validateExample() {
 
 	defer profile.Start().Stop()
 
@@ -20,13 +40,9 @@ func main() {
 	iter := 20000
 	for i := 0; i < iter; i++ {
 
-		b, size, err := gojson.ParseJSON(example)
-		must.BeNil(err)
-		if len(example) != size {
-			panic(fmt.Sprintf("example size[%d] is different than parsed size[%d]", len(example), size))
-		}
+		must.BeNil(gojson.ValidateJSON(example))
 		if !silent {
-			fmt.Printf("%s\n", string(b))
+			fmt.Printf("%s\n", string(example))
 		}
 
 	}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+// minCmd prints every json value in the files named, or stdin if there
+// are none, without insignificant whitespace, one value per line
+func minCmd(args []string) int {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+		fmt.Fprintln(os.Stderr, "usage: gj min [file.json ...]")
+		return 2
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if len(args) == 0 {
+		if err := minify(w, os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "gj min: <stdin>: %v\n", err)
+			return 5
+		}
+		return 0
+	}
+
+	for _, name := range args {
+		file, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj min: %v\n", err)
+			return 2
+		}
+		err = minify(w, file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj min: %s: %v\n", name, err)
+			return 5
+		}
+	}
+	return 0
+}
+
+// minify writes each value read from r to w compacted
+func minify(w io.Writer, r io.Reader) error {
+	var out []byte
+	d := gojson.NewDecoder(r)
+	for {
+		v, err := d.Value()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		out, err = gojson.Compact(out[:0], v)
+		if err != nil {
+			return err
+		}
+		out = append(out, '\n')
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+	}
+}
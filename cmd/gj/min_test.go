@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMinify(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "object", input: "{\n  \"a\": [ 1, 2 ],\n  \"b\": \" x \"\n}\n", expected: "{\"a\":[1,2],\"b\":\" x \"}\n"},
+		{name: "stream", input: "1\n[ ]\n {} ", expected: "1\n[]\n{}\n"},
+		{name: "empty", input: "  ", expected: ""},
+		{name: "invalid", input: `{"a": [1 2]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := minify(&out, strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jimmyjames85/gojson"
+)
+
+// queryCmd runs a jq filter over every json value in the files named, or
+// stdin if there are none, printing each output compactly on its own
+// line
+func queryCmd(args []string) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	raw := fs.Bool("r", false, "print strings without quotes or escapes")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gj query [-r] <filter> [file.json ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	f, err := parseFilter(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gj query: %v\n", err)
+		return 3
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	files := fs.Args()[1:]
+	if len(files) == 0 {
+		if err := runQuery(w, f, os.Stdin, *raw); err != nil {
+			fmt.Fprintf(os.Stderr, "gj query: <stdin>: %v\n", err)
+			return 5
+		}
+		return 0
+	}
+
+	for _, name := range files {
+		file, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj query: %v\n", err)
+			return 2
+		}
+		err = runQuery(w, f, file, *raw)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gj query: %s: %v\n", name, err)
+			return 5
+		}
+	}
+	return 0
+}
+
+// runQuery runs f over each value read from r and writes its outputs
+// to w
+func runQuery(w io.Writer, f filter, r io.Reader, raw bool) error {
+	d := gojson.NewDecoder(r)
+	for {
+		v, err := d.Value()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		out, err := f.eval(v)
+		if err != nil {
+			return err
+		}
+		for _, o := range out {
+			if raw && o.IsString() {
+				s, err := gojson.GetString(o)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(w, s)
+				continue
+			}
+			fmt.Fprintf(w, "%s\n", compact(o))
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	example, err := os.ReadFile("../../example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   string
+		input    string
+		raw      bool
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "identity is compact",
+			filter:   `.`,
+			input:    `{ "a" : [ 1, 2 ] }`,
+			expected: `{"a":[1,2]}`,
+		},
+		{
+			name:     "empty filter",
+			filter:   ``,
+			input:    `1 2`,
+			expected: "1\n2",
+		},
+		{
+			name:     "fields and indexes",
+			filter:   `.[3].name.first, .[-1]["name"].last, .[0].friends[1].id`,
+			input:    string(example),
+			expected: "\"Hayden\"\n\"Robertson\"\n1",
+		},
+		{
+			name:     "missing is null",
+			filter:   `.a.b, .[0]?`,
+			input:    `{}`,
+			expected: "null",
+		},
+		{
+			name:    "can't index a number",
+			filter:  `.a`,
+			input:   `1`,
+			wantErr: true,
+		},
+		{
+			name:     "iterate and pipe",
+			filter:   `.[] | .a`,
+			input:    `[{"a": 1}, {"a": "x"}]`,
+			expected: "1\n\"x\"",
+		},
+		{
+			name:     "recurse",
+			filter:   `[..]`,
+			input:    `[1, [2]]`,
+			expected: `[[1,[2]],1,[2],2]`,
+		},
+		{
+			name:     "select and map",
+			filter:   `map(select(.age > 30) | .email)`,
+			input:    string(example),
+			expected: `["jerry.hensley@qaboos.co.uk","tamera.wiley@apexia.tv","whitehead.robertson@acium.me"]`,
+		},
+		{
+			name:     "keys and length",
+			filter:   `keys, length, (.b | length), (.c | length)`,
+			input:    `{"b": "héllo", "a": null, "c": -3}`,
+			expected: "[\"a\",\"b\",\"c\"]\n3\n5\n3",
+		},
+		{
+			name:     "object construction",
+			filter:   `.[0] | {name: .name.first, "age", (.eyeColor): true, tags: .tags | length}`,
+			input:    string(example),
+			expected: `{"name":"Aurora","age":29,"green":true,"tags":5}`,
+		},
+		{
+			name:     "one object for each output",
+			filter:   `{a: (1, 2), b: .}`,
+			input:    `"x"`,
+			expected: "{\"a\":1,\"b\":\"x\"}\n{\"a\":2,\"b\":\"x\"}",
+		},
+		{
+			name:     "interpolation",
+			filter:   `.[] | "\(.name.first) \(.name.last) is \(.age) \("\t")"`,
+			input:    `[{"name": {"first": "A", "last": "B"}, "age": 1}]`,
+			expected: `"A B is 1 \t"`,
+		},
+		{
+			name:     "raw strings",
+			filter:   `.a, .b`,
+			input:    `{"a": "x\ny", "b": [1]}`,
+			raw:      true,
+			expected: "x\ny\n[1]",
+		},
+		{
+			name:     "comparisons",
+			filter:   `[1 < 2, "a" > "b", [1, 2] == [1, 2.0], {"a": 1} != {"a": 1}, null < false, 1 < "1"]`,
+			input:    `null`,
+			expected: `[true,false,true,false,true,true]`,
+		},
+		{
+			name:     "and, or, not and alternative",
+			filter:   `[(true and null), (false or 1), (null | not), (.missing // "default"), (empty // 2)]`,
+			input:    `{}`,
+			expected: `[false,true,true,"default",2]`,
+		},
+		{
+			name:     "type",
+			filter:   `[.[] | type]`,
+			input:    `[{}, [], "", 0, true, null]`,
+			expected: `["object","array","string","number","boolean","null"]`,
+		},
+		{
+			name:    "undefined function",
+			filter:  `nope`,
+			input:   `1`,
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			filter:  `.a[`,
+			input:   `1`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		f, err := parseFilter(tc.filter)
+
+		var out strings.Builder
+		if err == nil {
+			err = runQuery(&out, f, strings.NewReader(tc.input), tc.raw)
+		}
+
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error: got %q", tc.name, out.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		if actual := strings.TrimSuffix(out.String(), "\n"); actual != tc.expected {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", tc.name, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package gojson
+
+// Compact appends the json document src to dst without insignificant
+// whitespace. Strings are copied as they are, escapes and all. Nothing
+// is allocated beyond growing dst, so reusing a buffer with enough room
+// costs nothing. If src is invalid dst is returned unchanged along with
+// the error.
+func Compact(dst, src []byte) ([]byte, error) {
+	if err := ValidateJSON(src); err != nil {
+		return dst, err
+	}
+
+	start := 0 // src[start:i] is yet to be appended
+	for i := 0; i < len(src); {
+		switch src[i] {
+		case '"':
+			// src is valid so the string ends at the first '"' that
+			// isn't escaped
+			for i++; src[i] != '"'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+			i++
+		case ' ', '\t', '\n', '\r':
+			dst = append(dst, src[start:i]...)
+			_, n := ParseWhitespace(src[i:])
+			i += n
+			start = i
+		default:
+			i++
+		}
+	}
+	return append(dst, src[start:]...), nil
+}
@@ -0,0 +1,109 @@
+package gojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  error
+	}{
+		{name: "literal", input: " \t\r\n1.50\n", expected: `1.50`},
+		{name: "already compact", input: `{"a":[1,2]}`, expected: `{"a":[1,2]}`},
+		{name: "whitespace everywhere", input: "{ \"a\" :\n\t[ 1 ,\r\n 2 ] , \"b\" : { } }", expected: `{"a":[1,2],"b":{}}`},
+		{name: "strings are kept", input: `[ " a b ", "\" ", "\\", "\\\" ]" ]`, expected: `[" a b ","\" ","\\","\\\" ]"]`},
+		{name: "escapes are kept", input: `[ "é\/\n" ]`, expected: `["é\/\n"]`},
+		{name: "invalid", input: `[1 2]`, wantErr: ErrInvalidArrayClose},
+		{name: "trailing data", input: `1 2`, wantErr: ErrTrailingData},
+		{name: "empty", input: ` `, wantErr: ErrEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Compact([]byte("dst:"), []byte(tt.input))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if string(actual) != "dst:" {
+					t.Errorf("dst should be unchanged, got %q", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(actual) != "dst:"+tt.expected {
+				t.Errorf("expected %q, got %q", "dst:"+tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCompactMatchesEncodingJSON(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	var expected bytes.Buffer
+	if err := json.Compact(&expected, example); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := Compact(nil, example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected.Bytes()) {
+		t.Errorf("expected\n%s\ngot\n%s", expected.Bytes(), actual)
+	}
+}
+
+func TestCompactAllocs(t *testing.T) {
+	example := readFile(t, "example.json")
+	dst := make([]byte, 0, len(example))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := Compact(dst, example); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("unexpected allocations: %v", allocs)
+	}
+}
+
+func BenchmarkCompact(b *testing.B) {
+	example := readFile(b, "example.json")
+	dst := make([]byte, 0, len(example))
+
+	b.SetBytes(int64(len(example)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Compact(dst, example); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompactEncodingJSON(b *testing.B) {
+	example := readFile(b, "example.json")
+	var dst bytes.Buffer
+	dst.Grow(len(example))
+
+	b.SetBytes(int64(len(example)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		dst.Reset()
+		if err := json.Compact(&dst, example); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,621 @@
+package gojson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrDecoderState is returned when a Decoder method is called out of
+// turn, e.g. Key inside an array or a value in an object before its Key
+var ErrDecoderState = fmt.Errorf("decoder: call is not valid here")
+
+// minRead is the smallest read the Decoder asks its io.Reader for
+const minRead = 4096
+
+// Decoder reads json from an io.Reader one piece of the grammar at a
+// time. Only the token being decoded needs to fit in memory, so a
+// document of any size can be walked, e.g.
+//
+//	d := NewDecoder(r)
+//	d.BeginArray()
+//	for more, _ := d.More(); more; more, _ = d.More() {
+//		d.BeginObject()
+//		for more, _ := d.More(); more; more, _ = d.More() {
+//			key, _ := d.Key()
+//			...
+//		}
+//		d.EndObject()
+//	}
+//	d.EndArray()
+//
+// Commas are consumed for you. Slices returned by a Decoder point into
+// its buffer and are only valid until the next call.
+//
+// The Decoder reads a stream of top-level values separated by
+// whitespace. io.EOF is returned when there are no more of them.
+type Decoder struct {
+	r    io.Reader
+	rerr error // error from r, io.EOF once r is drained
+
+	buf []byte
+	off int // buf[off:] has not been consumed
+
+	// position of buf[off] in the stream
+	pos  int64
+	line int
+	col  int
+
+	stack []container
+	err   error // a *SyntaxError stops the Decoder for good
+
+	tokenEnd tokenEnd // how far scan has looked for the end of the token
+
+	opts ParseOptions
+}
+
+// container is an object or array the Decoder is inside of
+type container struct {
+	open  byte // '{' or '['
+	state int
+	n     int // members or elements decoded so far
+}
+
+const (
+	stateEmpty = iota // nothing decoded yet
+	stateKey          // a key has been decoded, its value comes next
+	stateValue        // a value has been decoded, ',' comes next
+	stateNext         // a ',' has been consumed, something must follow it
+)
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:    r,
+		line: 1,
+		col:  1,
+		opts: DefaultParseOptions,
+	}
+}
+
+// InputOffset returns the offset in the stream of the next unread byte
+func (d *Decoder) InputOffset() int64 { return d.pos }
+
+// Peek returns the first byte of whatever comes next, skipping
+// whitespace and the ',' before the next member or element.
+func (d *Decoder) Peek() (byte, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	c, err := d.peek()
+	if err != nil || c != ',' || len(d.stack) == 0 ||
+		d.stack[len(d.stack)-1].state != stateValue {
+		return c, err
+	}
+
+	if err := d.comma(); err != nil {
+		return 0, err
+	}
+	return d.peek()
+}
+
+// More reports whether the object or array being decoded has another
+// member or element. Outside of any object or array it reports whether
+// the stream has another value.
+func (d *Decoder) More() (bool, error) {
+	if d.err != nil {
+		return false, d.err
+	}
+
+	c, err := d.peek()
+	if len(d.stack) == 0 {
+		if err == io.EOF {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if err != nil {
+		return false, d.eof(err, "value")
+	}
+
+	top := d.stack[len(d.stack)-1]
+	return top.state == stateNext || c != closer(top.open), nil
+}
+
+func (d *Decoder) BeginObject() error { return d.begin('{', "object", ErrInvalidObjectOpen) }
+
+func (d *Decoder) EndObject() error { return d.end('{', "object", ErrInvalidObjectClose) }
+
+func (d *Decoder) BeginArray() error { return d.begin('[', "array", ErrInvalidArrayOpen) }
+
+func (d *Decoder) EndArray() error { return d.end('[', "array", ErrInvalidArrayClose) }
+
+// Key decodes the key of the next member of an object along with the
+// ':' that follows it. The member's value is decoded by the next call.
+func (d *Decoder) Key() (String, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.stack) == 0 || d.stack[len(d.stack)-1].open != '{' ||
+		d.stack[len(d.stack)-1].state == stateKey {
+		return nil, ErrDecoderState
+	}
+	if err := d.comma(); err != nil {
+		return nil, err
+	}
+
+	b, err := d.scan(func(b []byte) (int, error) {
+		// member
+		//     ws string ws ':' element
+		//
+		// the leading ws has already been consumed
+		p := parser{ParseOptions: d.opts}
+		_, c, err := p.str(b)
+		if err != nil {
+			return 0, err
+		}
+		_, n := ParseWhitespace(b[c:])
+		c += n
+		if c == len(b) || b[c] != ':' {
+			return 0, syntaxError(b, c, "member", ErrInvalidMemberMissingSep)
+		}
+		return c + 1, nil
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	d.stack[len(d.stack)-1].state = stateKey
+
+	// b is the key, trailing whitespace and ':'
+	key, _, _ := ParseString(b)
+	return key, nil
+}
+
+func (d *Decoder) String() (String, error) {
+	b, err := d.token(func(b []byte) (int, error) {
+		p := parser{ParseOptions: d.opts}
+		_, c, err := p.str(b)
+		return c, err
+	}, false)
+	return String(b), err
+}
+
+func (d *Decoder) Number() (Number, error) {
+	b, err := d.token(func(b []byte) (int, error) {
+		_, c, err := ParseNumber(b)
+		if err != nil {
+			return 0, err
+		}
+		return c, checkNumberEnd(b, c)
+	}, true)
+	return Number(b), err
+}
+
+func (d *Decoder) Boolean() (Boolean, error) {
+	b, err := d.token(func(b []byte) (int, error) {
+		_, c, err := ParseBoolean(b)
+		return c, err
+	}, false)
+	return Boolean(b), err
+}
+
+func (d *Decoder) Null() (Null, error) {
+	b, err := d.token(func(b []byte) (int, error) {
+		_, c, err := ParseNull(b)
+		return c, err
+	}, false)
+	return Null(b), err
+}
+
+// Value decodes the next value whole. Unlike the rest of the Decoder
+// an object or array has to fit in memory to be decoded this way.
+func (d *Decoder) Value() (Value, error) {
+	b, err := d.token(func(b []byte) (int, error) {
+		p := parser{ParseOptions: d.opts, depth: len(d.stack)}
+		_, c, err := p.value(b)
+		return c, err
+	}, true)
+	return Value(b), err
+}
+
+// Skip consumes the next value without keeping more than one token of
+// it in memory
+func (d *Decoder) Skip() error {
+	c, err := d.Peek()
+	if err != nil {
+		return d.eof(err, "value")
+	}
+
+	switch c {
+	case '{':
+		if err := d.BeginObject(); err != nil {
+			return err
+		}
+		for {
+			more, err := d.More()
+			if err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+			if _, err := d.Key(); err != nil {
+				return err
+			}
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+		return d.EndObject()
+	case '[':
+		if err := d.BeginArray(); err != nil {
+			return err
+		}
+		for {
+			more, err := d.More()
+			if err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+		return d.EndArray()
+	}
+
+	_, err = d.Value()
+	return err
+}
+
+func closer(open byte) byte {
+	if open == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+func (d *Decoder) begin(open byte, expected string, sentinel error) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	_, err := d.token(func(b []byte) (int, error) {
+		if len(b) == 0 || b[0] != open {
+			return 0, syntaxError(b, 0, expected, sentinel)
+		}
+		if d.opts.MaxDepth > 0 && len(d.stack) >= d.opts.MaxDepth {
+			return 0, syntaxError(b, 0, "value", ErrMaxDepthExceeded)
+		}
+		return 1, nil
+	}, false)
+	if err != nil {
+		return err
+	}
+
+	d.stack = append(d.stack, container{open: open})
+	return nil
+}
+
+func (d *Decoder) end(open byte, expected string, sentinel error) error {
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.stack) == 0 || d.stack[len(d.stack)-1].open != open ||
+		d.stack[len(d.stack)-1].state == stateKey {
+		return ErrDecoderState
+	}
+
+	c, err := d.peek()
+	if err != nil {
+		return d.eof(err, expected)
+	}
+	if d.stack[len(d.stack)-1].state == stateNext {
+		// a trailing ','
+		return d.fail(syntaxError(d.buf[d.off:], 0, "value", ErrUnsupported))
+	}
+	if c != closer(open) {
+		return d.fail(syntaxError(d.buf[d.off:], 0, expected, sentinel))
+	}
+	d.advance(1)
+
+	d.stack = d.stack[:len(d.stack)-1]
+	d.decoded()
+	return nil
+}
+
+// token decodes a value with parse. It checks the value is allowed
+// where the Decoder is and consumes the ',' before it.
+func (d *Decoder) token(parse func([]byte) (int, error), number bool) ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.stack) > 0 {
+		top := d.stack[len(d.stack)-1]
+		if top.open == '{' && top.state != stateKey {
+			return nil, ErrDecoderState
+		}
+	}
+	if err := d.comma(); err != nil {
+		return nil, err
+	}
+
+	b, err := d.scan(parse, number)
+	if err != nil {
+		return nil, err
+	}
+
+	d.decoded()
+	return b, nil
+}
+
+// comma consumes the whitespace before the next member or element, and
+// the ',' too if one is required
+func (d *Decoder) comma() error {
+	c, err := d.peek()
+	if len(d.stack) == 0 && err == io.EOF {
+		return io.EOF // clean end of the stream
+	}
+	if err != nil {
+		return d.eof(err, "value")
+	}
+
+	if len(d.stack) == 0 || d.stack[len(d.stack)-1].state != stateValue {
+		return nil
+	}
+
+	top := &d.stack[len(d.stack)-1]
+	if c != ',' {
+		sentinel := ErrInvalidArrayClose
+		expected := "array"
+		if top.open == '{' {
+			sentinel = ErrInvalidObjectClose
+			expected = "object"
+		}
+		return d.fail(syntaxError(d.buf[d.off:], 0, expected, sentinel))
+	}
+	if d.opts.MaxMembers > 0 && top.n == d.opts.MaxMembers {
+		expected := "elements"
+		if top.open == '{' {
+			expected = "members"
+		}
+		return d.fail(syntaxError(d.buf[d.off:], 0, expected, ErrMaxMembersExceeded))
+	}
+	d.advance(1)
+	top.state = stateNext
+
+	_, err = d.peek()
+	if err != nil {
+		return d.eof(err, "value")
+	}
+	return nil
+}
+
+// decoded records that a value has been decoded in the current container
+func (d *Decoder) decoded() {
+	if len(d.stack) > 0 {
+		d.stack[len(d.stack)-1].state = stateValue
+		d.stack[len(d.stack)-1].n++
+	}
+}
+
+// peek skips whitespace and returns the next byte without consuming it
+func (d *Decoder) peek() (byte, error) {
+	for {
+		_, n := ParseWhitespace(d.buf[d.off:])
+		d.advance(n)
+		if d.off < len(d.buf) {
+			return d.buf[d.off], nil
+		}
+		if err := d.refill(); err == ErrMaxBytesExceeded {
+			return 0, d.fail(syntaxError(d.buf[d.off:], 0, "value", err))
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// scan runs parse over the buffered input, reading more whenever parse
+// fails because it ran out of input. parse returns how much of b it
+// consumed. Since there's no way to tell where a number ends until
+// something that isn't part of it shows up, if number is set then a
+// number that consumes everything buffered also causes a read.
+//
+// parse starts from the beginning of the token again, so rather than
+// after every read it is run again once the end of the token has been
+// read. A reader that returns a byte at a time then costs no more than
+// one that fills the buffer.
+func (d *Decoder) scan(parse func([]byte) (int, error), number bool) ([]byte, error) {
+	d.tokenEnd = tokenEnd{}
+	for {
+		b := d.buf[d.off:]
+		c, err := parse(b)
+
+		var se *SyntaxError
+		more := (err == nil && number && c == len(b) && (b[0] == '-' || IsDigit(b[0]))) ||
+			(errors.As(err, &se) && se.Offset == len(b))
+
+		if more && d.rerr == nil {
+			if err := d.refill(); err != nil && err != io.EOF {
+				return nil, err
+			}
+			for d.rerr == nil && !d.tokenEnd.find(d.buf[d.off:]) {
+				if err := d.refill(); err != nil && err != io.EOF {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if more && d.rerr == ErrMaxBytesExceeded {
+			if se == nil {
+				se = syntaxError(b, len(b), "number", nil).(*SyntaxError)
+			}
+			se.Err = ErrMaxBytesExceeded
+			return nil, d.fail(se)
+		}
+		if more && d.rerr != io.EOF {
+			return nil, d.rerr
+		}
+
+		if err != nil {
+			return nil, d.fail(err)
+		}
+
+		d.advance(c)
+		return b[:c], nil
+	}
+}
+
+// tokenEnd finds where the token at the front of the buffer ends,
+// carrying on from where it stopped as more is read. It only follows
+// strings and brackets, checking the token is valid is up to parse.
+type tokenEnd struct {
+	n      int // bytes of the token looked at so far
+	depth  int // of brackets
+	str    bool
+	escape bool // the last byte was a '\' in a string
+	done   bool
+}
+
+// find reports whether the token at the front of b ends in b. Once it
+// has, find keeps reporting true.
+func (t *tokenEnd) find(b []byte) bool {
+	for ; !t.done && t.n < len(b); t.n++ {
+		c := b[t.n]
+		switch {
+		case t.escape:
+			t.escape = false
+		case t.str:
+			if c == '\\' {
+				t.escape = true
+			} else if c == '"' {
+				t.str = false
+				t.done = t.depth == 0
+			}
+		case c == '"':
+			t.str = true
+		case c == '{' || c == '[':
+			t.depth++
+		case c == '}' || c == ']':
+			t.depth--
+			t.done = t.depth <= 0
+		case t.depth == 0 && t.n > 0 && !isLiteral(c):
+			// the end of a number, true, false or null
+			t.done = true
+		}
+	}
+	return t.done
+}
+
+// isLiteral reports whether c can be part of a number, true, false or
+// null
+func isLiteral(c byte) bool {
+	return IsDigit(c) || c >= 'a' && c <= 'z' || c == 'E' || c == '-' || c == '+' || c == '.'
+}
+
+// advance consumes n bytes of buf
+func (d *Decoder) advance(n int) {
+	b := d.buf[d.off : d.off+n]
+	if nl := bytes.Count(b, []byte{'\n'}); nl > 0 {
+		d.line += nl
+		d.col = n - bytes.LastIndexByte(b, '\n')
+	} else {
+		d.col += n
+	}
+	d.pos += int64(n)
+	d.off += n
+}
+
+// refill reads more of r into buf, moving the unconsumed bytes to the
+// front first. The buffer only grows when a token is too big for it.
+func (d *Decoder) refill() error {
+	if d.rerr != nil {
+		return d.rerr
+	}
+
+	if d.off > 0 {
+		n := copy(d.buf, d.buf[d.off:])
+		d.buf = d.buf[:n]
+		d.off = 0
+	}
+
+	// grow when whatever is left of the token fills over half the buffer
+	if cap(d.buf) == 0 || cap(d.buf)-len(d.buf) < len(d.buf) {
+		buf := make([]byte, len(d.buf), 2*cap(d.buf)+minRead)
+		copy(buf, d.buf)
+		d.buf = buf
+	}
+
+	end := cap(d.buf)
+	if d.opts.MaxBytes > 0 {
+		left := d.opts.MaxBytes - int(d.pos) - len(d.buf)
+		if left <= 0 {
+			return d.limit()
+		}
+		if len(d.buf)+left < end {
+			end = len(d.buf) + left
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		n, err := d.r.Read(d.buf[len(d.buf):end])
+		d.buf = d.buf[:len(d.buf)+n]
+		if err != nil {
+			d.rerr = err
+		}
+		if n > 0 || err != nil {
+			return nil
+		}
+	}
+
+	d.rerr = io.ErrNoProgress
+	return d.rerr
+}
+
+// limit is called once MaxBytes have been read. The stream may end
+// right there, so it reads one more byte to find out whether going on
+// would exceed the limit.
+func (d *Decoder) limit() error {
+	var one [1]byte
+	for i := 0; i < 100; i++ {
+		n, err := d.r.Read(one[:])
+		if n > 0 {
+			d.rerr = ErrMaxBytesExceeded
+			return nil
+		}
+		if err != nil {
+			d.rerr = err
+			return nil
+		}
+	}
+
+	d.rerr = io.ErrNoProgress
+	return d.rerr
+}
+
+// fail positions a *SyntaxError from parsing buf[off:] in the stream
+// and stops the Decoder
+func (d *Decoder) fail(err error) error {
+	if e, ok := err.(*SyntaxError); ok {
+		e.Offset += int(d.pos)
+		if e.Line == 1 {
+			e.Column += d.col - 1
+		}
+		e.Line += d.line - 1
+		d.err = e
+	}
+	return err
+}
+
+// eof turns running out of input part way through a document into a
+// *SyntaxError, any other error is returned as is
+func (d *Decoder) eof(err error, expected string) error {
+	if err != io.EOF {
+		return err
+	}
+	return d.fail(syntaxError(d.buf[d.off:], 0, expected, ErrEOF))
+}
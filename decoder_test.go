@@ -0,0 +1,357 @@
+package gojson
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// walk decodes everything from d and returns the tokens it saw, one per
+// line
+func walk(t *testing.T, d *Decoder) (string, error) {
+	t.Helper()
+
+	var out strings.Builder
+	var value func() error
+	value = func() error {
+		c, err := d.Peek()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case c == '{':
+			if err := d.BeginObject(); err != nil {
+				return err
+			}
+			out.WriteString("{\n")
+			for {
+				more, err := d.More()
+				if err != nil {
+					return err
+				}
+				if !more {
+					break
+				}
+				key, err := d.Key()
+				if err != nil {
+					return err
+				}
+				out.WriteString(string(key) + ":\n")
+				if err := value(); err != nil {
+					return err
+				}
+			}
+			out.WriteString("}\n")
+			return d.EndObject()
+		case c == '[':
+			if err := d.BeginArray(); err != nil {
+				return err
+			}
+			out.WriteString("[\n")
+			for {
+				more, err := d.More()
+				if err != nil {
+					return err
+				}
+				if !more {
+					break
+				}
+				if err := value(); err != nil {
+					return err
+				}
+			}
+			out.WriteString("]\n")
+			return d.EndArray()
+		case c == '"':
+			s, err := d.String()
+			out.WriteString(string(s) + "\n")
+			return err
+		case c == 't' || c == 'f':
+			b, err := d.Boolean()
+			out.WriteString(string(b) + "\n")
+			return err
+		case c == 'n':
+			n, err := d.Null()
+			out.WriteString(string(n) + "\n")
+			return err
+		case c == '-' || IsDigit(c):
+			n, err := d.Number()
+			out.WriteString(string(n) + "\n")
+			return err
+		default:
+			v, err := d.Value()
+			out.WriteString(string(v) + "\n")
+			return err
+		}
+	}
+
+	for {
+		more, err := d.More()
+		if err != nil {
+			return out.String(), err
+		}
+		if !more {
+			return out.String(), nil
+		}
+		if err := value(); err != nil {
+			return out.String(), err
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "scalars",
+			input:    ` "jim" -12.5e3 true false null 0 `,
+			expected: "\"jim\"\n-12.5e3\ntrue\nfalse\nnull\n0\n",
+		},
+		{
+			name:     "nested",
+			input:    `{"a": [1, {"b": null}, []], "c" : {}}`,
+			expected: "{\n\"a\":\n[\n1\n{\n\"b\":\nnull\n}\n[\n]\n]\n\"c\":\n{\n}\n}\n",
+		},
+		{
+			name:     "stream of documents",
+			input:    "[1]\n[2]\n",
+			expected: "[\n1\n]\n[\n2\n]\n",
+		},
+		{
+			name:     "empty stream",
+			input:    "  \n ",
+			expected: "",
+		},
+		{
+			name:    "trailing comma",
+			input:   `[1, 2,]`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "missing comma",
+			input:   `[1 2]`,
+			wantErr: ErrInvalidArrayClose,
+		},
+		{
+			name:    "missing colon",
+			input:   `{"a" 1}`,
+			wantErr: ErrInvalidMemberMissingSep,
+		},
+		{
+			name:    "unterminated",
+			input:   `{"a": [1, 2`,
+			wantErr: ErrEOF,
+		},
+		{
+			name:    "unterminated number",
+			input:   `[1.`,
+			wantErr: ErrInvalidNumber,
+		},
+		{
+			name:    "bad escape",
+			input:   `["\x"]`,
+			wantErr: ErrInvalidEscape,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// one byte at a time makes every token straddle a refill
+			d := NewDecoder(iotest.OneByteReader(strings.NewReader(tc.input)))
+
+			actual, err := walk(t, d)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("unexpected error: wanted %v got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			if tc.expected != actual {
+				t.Errorf("unexpected return: wanted %q got %q", tc.expected, actual)
+			}
+			if d.InputOffset() != int64(len(tc.input)) {
+				t.Errorf("unexpected offset: wanted %d got %d", len(tc.input), d.InputOffset())
+			}
+		})
+	}
+}
+
+func TestDecoderSyntaxErrorPosition(t *testing.T) {
+	example := readFile(t, "badExample.json")
+
+	d := NewDecoder(iotest.HalfReader(bytes.NewReader(example)))
+	err := d.Skip()
+
+	var se *SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf("expecting *SyntaxError but got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidEscape) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if se.Offset != 340 || se.Line != 15 || se.Column != 24 {
+		t.Errorf("unexpected position: got %d:%d (offset %d)", se.Line, se.Column, se.Offset)
+	}
+
+	// the Decoder is stopped for good
+	if _, err := d.Value(); err != se {
+		t.Errorf("expecting the same error again but got %v", err)
+	}
+}
+
+func TestDecoderState(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a": [1]}`))
+
+	if _, err := d.Key(); err != ErrDecoderState {
+		t.Errorf("Key outside an object: got %v", err)
+	}
+	if err := d.BeginObject(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Number(); err != ErrDecoderState {
+		t.Errorf("value before key: got %v", err)
+	}
+	if _, err := d.Key(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EndObject(); err != ErrDecoderState {
+		t.Errorf("EndObject before value: got %v", err)
+	}
+	if err := d.BeginArray(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EndObject(); err != ErrDecoderState {
+		t.Errorf("EndObject inside array: got %v", err)
+	}
+}
+
+func TestDecoderValueAndSkip(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`[{"skip": [1, {"me": "please"}]}, {"keep": 12}, 34]`))
+
+	if err := d.BeginArray(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Skip(); err != nil {
+		t.Fatal(err)
+	}
+	v, err := d.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v) != `{"keep": 12}` {
+		t.Errorf("unexpected value: %q", v)
+	}
+	v, err = d.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v) != `34` {
+		t.Errorf("unexpected value: %q", v)
+	}
+	if err := d.EndArray(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Value(); err != io.EOF {
+		t.Errorf("expecting io.EOF but got %v", err)
+	}
+}
+
+// arrayReader streams `[0,1,2,...]` with n elements without ever holding
+// the document in memory
+type arrayReader struct {
+	n, i int
+	buf  []byte
+}
+
+func (r *arrayReader) Read(p []byte) (int, error) {
+	for len(r.buf) < len(p) && r.i <= r.n {
+		switch {
+		case r.i == 0:
+			r.buf = append(r.buf, '[')
+		case r.i == r.n:
+			r.buf = append(r.buf, "0]"...)
+		default:
+			r.buf = append(r.buf, "1234567890,"...)
+		}
+		r.i++
+	}
+	if len(r.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func TestDecoderConstantMemory(t *testing.T) {
+	d := NewDecoder(&arrayReader{n: 1000000})
+
+	if err := d.Skip(); err != nil {
+		t.Fatal(err)
+	}
+	if d.InputOffset() != 1+999999*11+2 {
+		t.Fatalf("didn't read the whole document: %d", d.InputOffset())
+	}
+	if cap(d.buf) > 2*minRead {
+		t.Errorf("buffer grew to %d bytes", cap(d.buf))
+	}
+}
+
+func TestDecoderShortReads(t *testing.T) {
+	// parsing the string again after every byte would take hours
+	long := strings.Repeat("a", 1<<20)
+	input := `["` + long + `", {"` + long + `": [` + strings.Repeat("1,", 1<<19) + `1]}]`
+	d := NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+
+	if err := d.BeginArray(); err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != len(long)+2 {
+		t.Errorf("unexpected string of %d bytes", len(s))
+	}
+	if err := d.BeginObject(); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := d.Key(); err != nil || len(key) != len(long)+2 {
+		t.Fatalf("unexpected key of %d bytes: %v", len(key), err)
+	}
+	v, err := d.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 1<<20+3 {
+		t.Errorf("unexpected value of %d bytes", len(v))
+	}
+	if err := d.EndObject(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EndArray(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecoderExample(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	d := NewDecoder(iotest.OneByteReader(bytes.NewReader(example)))
+	if _, err := walk(t, d); err != nil {
+		t.Fatal(err)
+	}
+	if d.InputOffset() != int64(len(example)) {
+		t.Errorf("unexpected offset: wanted %d got %d", len(example), d.InputOffset())
+	}
+}
@@ -0,0 +1,517 @@
+package gojson
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// ChangeKind is what a Change did to the value at its Path
+type ChangeKind int
+
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeRemoved
+	ChangeReplaced
+	ChangeMoved
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeReplaced:
+		return "replaced"
+	case ChangeMoved:
+		return "moved"
+	}
+	return "ChangeKind(?)"
+}
+
+// Change is one difference found by Diff. Old is nil for an added
+// value and New is nil for a removed one. A moved value was at From
+// and goes to Path, Old and New are both the value. Old and New point
+// into the documents that were compared.
+type Change struct {
+	Kind ChangeKind
+	Path Pointer
+	From Pointer // only for ChangeMoved
+	Old  Value
+	New  Value
+}
+
+// ArrayMatch is how Diff pairs up the elements of two arrays
+type ArrayMatch int
+
+const (
+	// ArrayMatchIndex pairs elements at the same index, so inserting
+	// at the front of an array changes every element after it
+	ArrayMatchIndex ArrayMatch = iota
+
+	// ArrayMatchLCS keeps the longest common subsequence of elements
+	// and reports the rest as added and removed, or as changed in place
+	// where a removal is followed by an addition
+	ArrayMatchLCS
+
+	// ArrayMatchKey pairs objects that have the same value for the
+	// member DiffOptions.Key, wherever they are in the array. An element
+	// that is somewhere else in b is moved there, and elements without
+	// the key are only ever added or removed.
+	ArrayMatchKey
+)
+
+// DiffOptions controls how Diff compares arrays
+type DiffOptions struct {
+	Arrays ArrayMatch
+
+	// Key is the member ArrayMatchKey pairs objects by, e.g. "_id"
+	Key string
+}
+
+// Diff returns the changes that turn the json document a into b,
+// matching arrays by index. Values are compared as Equal compares them,
+// so whitespace, key order and how numbers and strings are written
+// don't count as changes. An object with a key more than once that
+// isn't Equal to the other is replaced as a whole.
+func Diff(a, b []byte) ([]Change, error) {
+	return DiffOptions{}.Diff(a, b)
+}
+
+// Diff is Diff matching arrays as o says. Changes are in an order they
+// can be applied in, as JSON Patch applies them, so the path of a
+// change to an array is its index once the changes before it are made.
+func (o DiffOptions) Diff(a, b []byte) ([]Change, error) {
+	if err := ValidateJSON(a); err != nil {
+		return nil, err
+	}
+	if err := ValidateJSON(b); err != nil {
+		return nil, err
+	}
+	_, c := ParseWhitespace(a)
+	_, d := ParseWhitespace(b)
+	av, _, _ := ParseValue(a[c:])
+	bv, _, _ := ParseValue(b[d:])
+
+	df := differ{opts: o}
+	if err := df.diff(Pointer{}, av, bv); err != nil {
+		return nil, err
+	}
+	return df.changes, nil
+}
+
+type differ struct {
+	opts    DiffOptions
+	changes []Change
+}
+
+func (df *differ) add(kind ChangeKind, path Pointer, old, new Value) {
+	df.changes = append(df.changes, Change{Kind: kind, Path: copyPointer(path), Old: old, New: new})
+}
+
+// move adds moving the value v from the index from to the index to of
+// the array at path
+func (df *differ) move(path Pointer, from, to int, v Value) {
+	df.changes = append(df.changes, Change{
+		Kind: ChangeMoved,
+		Path: copyPointer(append(path, strconv.Itoa(to))),
+		From: copyPointer(append(path, strconv.Itoa(from))),
+		Old:  v,
+		New:  v,
+	})
+}
+
+// copyPointer copies p, paths are appended to as the documents are
+// walked
+func copyPointer(p Pointer) Pointer {
+	c := make(Pointer, len(p))
+	copy(c, p)
+	return c
+}
+
+// diff adds the changes from a to b at path
+func (df *differ) diff(path Pointer, a, b Value) error {
+	if eq, err := equal(a, b); eq || err != nil {
+		return err
+	}
+
+	switch {
+	case a.Kind() == KindObject && b.Kind() == KindObject:
+		return df.object(path, a, b)
+	case a.Kind() == KindArray && b.Kind() == KindArray:
+		switch df.opts.Arrays {
+		case ArrayMatchLCS:
+			return df.lcs(path, a, b)
+		case ArrayMatchKey:
+			return df.keyed(path, a, b)
+		}
+		return df.indexed(path, a, b)
+	}
+	df.add(ChangeReplaced, path, a, b)
+	return nil
+}
+
+// objectMembers returns the unique keys of the valid object v in
+// order, where a key is repeated the last value wins and repeated is
+// true
+func objectMembers(v Value) (keys []string, byKey map[string]Value, repeated bool, err error) {
+	members, err := unquotedMembers(v)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	byKey = make(map[string]Value, len(members))
+	for _, m := range members {
+		k := string(m.key)
+		if _, ok := byKey[k]; ok {
+			repeated = true
+		} else {
+			keys = append(keys, k)
+		}
+		byKey[k] = m.value
+	}
+	return keys, byKey, repeated, nil
+}
+
+func (df *differ) object(path Pointer, a, b Value) error {
+	aKeys, aByKey, aRepeated, err := objectMembers(a)
+	if err != nil {
+		return err
+	}
+	bKeys, bByKey, bRepeated, err := objectMembers(b)
+	if err != nil {
+		return err
+	}
+	// a pointer can't tell repeated keys apart, so the whole object
+	// changes
+	if aRepeated || bRepeated {
+		df.add(ChangeReplaced, path, a, b)
+		return nil
+	}
+
+	for _, k := range aKeys {
+		p := append(path, k)
+		bv, ok := bByKey[k]
+		if !ok {
+			df.add(ChangeRemoved, p, aByKey[k], nil)
+			continue
+		}
+		if err := df.diff(p, aByKey[k], bv); err != nil {
+			return err
+		}
+	}
+	for _, k := range bKeys {
+		if _, ok := aByKey[k]; !ok {
+			df.add(ChangeAdded, append(path, k), nil, bByKey[k])
+		}
+	}
+	return nil
+}
+
+func (df *differ) indexed(path Pointer, a, b Value) error {
+	ae, be := elements(a), elements(b)
+
+	i := 0
+	for ; i < len(ae) && i < len(be); i++ {
+		if err := df.diff(append(path, strconv.Itoa(i)), ae[i], be[i]); err != nil {
+			return err
+		}
+	}
+	// from the end so the indexes stay put
+	for j := len(ae) - 1; j >= i; j-- {
+		df.add(ChangeRemoved, append(path, strconv.Itoa(j)), ae[j], nil)
+	}
+	for ; i < len(be); i++ {
+		df.add(ChangeAdded, append(path, strconv.Itoa(i)), nil, be[i])
+	}
+	return nil
+}
+
+func (df *differ) lcs(path Pointer, a, b Value) error {
+	ae, be := elements(a), elements(b)
+	ah, err := hashElements(ae)
+	if err != nil {
+		return err
+	}
+	bh, err := hashElements(be)
+	if err != nil {
+		return err
+	}
+	same := func(i, j int) bool {
+		if ah[i] != bh[j] {
+			return false
+		}
+		eq, _ := equal(ae[i], be[j])
+		return eq
+	}
+
+	// the common prefix and suffix are usually most of the arrays, only
+	// look for the lcs between them
+	p := 0
+	for p < len(ae) && p < len(be) && same(p, p) {
+		p++
+	}
+	q := 0
+	for q < len(ae)-p && q < len(be)-p && same(len(ae)-1-q, len(be)-1-q) {
+		q++
+	}
+	var matches []match
+	for k := 0; k < p; k++ {
+		matches = append(matches, match{k, k})
+	}
+	matches = hirschberg(same, p, len(ae)-q, p, len(be)-q, matches)
+	for k := q; k > 0; k-- {
+		matches = append(matches, match{len(ae) - k, len(be) - k})
+	}
+	matches = append(matches, match{len(ae), len(be)}) // the end
+
+	// idx is where we are in the array as changed so far
+	i, j, idx := 0, 0, 0
+	for _, m := range matches {
+		// between matches change elements into each other, then remove
+		// or add what is left over
+		for ; i < m.i && j < m.j; i, j, idx = i+1, j+1, idx+1 {
+			if err := df.diff(append(path, strconv.Itoa(idx)), ae[i], be[j]); err != nil {
+				return err
+			}
+		}
+		for ; i < m.i; i++ {
+			df.add(ChangeRemoved, append(path, strconv.Itoa(idx)), ae[i], nil)
+		}
+		for ; j < m.j; j, idx = j+1, idx+1 {
+			df.add(ChangeAdded, append(path, strconv.Itoa(idx)), nil, be[j])
+		}
+		i, j, idx = i+1, j+1, idx+1
+	}
+	return nil
+}
+
+// match is an element of a at i that is the same as one of b at j
+type match struct {
+	i, j int
+}
+
+// hirschberg appends the matches of an lcs of a[i0:i1] and b[j0:j1] to
+// matches, where same reports whether a[i] and b[j] are the same. It
+// takes time proportional to the product of the lengths but only space
+// proportional to their sum, by finding where the lcs of b and the
+// first half of a ends and recursing on each half.
+func hirschberg(same func(i, j int) bool, i0, i1, j0, j1 int, matches []match) []match {
+	if i0 == i1 || j0 == j1 {
+		return matches
+	}
+	if i1-i0 == 1 {
+		for j := j0; j < j1; j++ {
+			if same(i0, j) {
+				return append(matches, match{i0, j})
+			}
+		}
+		return matches
+	}
+
+	mid := (i0 + i1) / 2
+	front := lcsFront(same, i0, mid, j0, j1)
+	back := lcsBack(same, mid, i1, j0, j1)
+	split, longest := 0, -1
+	for k := range front {
+		if front[k]+back[k] > longest {
+			split, longest = k, front[k]+back[k]
+		}
+	}
+	matches = hirschberg(same, i0, mid, j0, j0+split, matches)
+	return hirschberg(same, mid, i1, j0+split, j1, matches)
+}
+
+// lcsFront returns the length of the lcs of a[i0:i1] and b[j0:j0+k] for
+// each k from 0 to j1-j0
+func lcsFront(same func(i, j int) bool, i0, i1, j0, j1 int) []int {
+	prev, cur := make([]int, j1-j0+1), make([]int, j1-j0+1)
+	for i := i0; i < i1; i++ {
+		for k := 1; k < len(cur); k++ {
+			switch {
+			case same(i, j0+k-1):
+				cur[k] = prev[k-1] + 1
+			case prev[k] >= cur[k-1]:
+				cur[k] = prev[k]
+			default:
+				cur[k] = cur[k-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsBack returns the length of the lcs of a[i0:i1] and b[j0+k:j1] for
+// each k from 0 to j1-j0
+func lcsBack(same func(i, j int) bool, i0, i1, j0, j1 int) []int {
+	prev, cur := make([]int, j1-j0+1), make([]int, j1-j0+1)
+	for i := i1 - 1; i >= i0; i-- {
+		for k := len(cur) - 2; k >= 0; k-- {
+			switch {
+			case same(i, j0+k):
+				cur[k] = prev[k+1] + 1
+			case prev[k] >= cur[k+1]:
+				cur[k] = prev[k]
+			default:
+				cur[k] = cur[k+1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+func (df *differ) keyed(path Pointer, a, b Value) error {
+	ae, be := elements(a), elements(b)
+	ak, err := df.elementKeys(ae)
+	if err != nil {
+		return err
+	}
+	bk, err := df.elementKeys(be)
+	if err != nil {
+		return err
+	}
+
+	// pair each element of a with the first unpaired element of b with
+	// the same key
+	pair := make([]int, len(ae))   // index into be, or -1
+	pairOf := make([]int, len(be)) // index into ae, or -1
+	byHash := map[uint64][]int{}
+	for j, k := range bk {
+		pairOf[j] = -1
+		if k.ok {
+			byHash[k.hash] = append(byHash[k.hash], j)
+		}
+	}
+	for i, k := range ak {
+		pair[i] = -1
+		if !k.ok {
+			continue
+		}
+		for _, j := range byHash[k.hash] {
+			if eq, _ := equal(k.value, bk[j].value); eq && pairOf[j] < 0 {
+				pair[i], pairOf[j] = j, i
+				break
+			}
+		}
+	}
+
+	// removals from the end, then b from the front, adding its element
+	// or moving the paired one into place and changing it. order is the
+	// index into ae of each element of the array as changed so far, -1
+	// for an added one.
+	var order []int
+	for i := len(ae) - 1; i >= 0; i-- {
+		if pair[i] < 0 {
+			df.add(ChangeRemoved, append(path, strconv.Itoa(i)), ae[i], nil)
+		}
+	}
+	for i := range ae {
+		if pair[i] >= 0 {
+			order = append(order, i)
+		}
+	}
+	for j := range be {
+		i := pairOf[j]
+		if i < 0 {
+			df.add(ChangeAdded, append(path, strconv.Itoa(j)), nil, be[j])
+			order = append(order[:j], append([]int{-1}, order[j:]...)...)
+			continue
+		}
+
+		// everything before j is in place, so the element is at j or after
+		from := j
+		for order[from] != i {
+			from++
+		}
+		if from != j {
+			df.move(path, from, j, ae[i])
+			copy(order[j+1:from+1], order[j:from])
+			order[j] = i
+		}
+		if err := df.diff(append(path, strconv.Itoa(j)), ae[i], be[j]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elementKey is the value of DiffOptions.Key in an array element
+type elementKey struct {
+	value Value
+	hash  uint64
+	ok    bool // the element is an object with the key
+}
+
+func (df *differ) elementKeys(elems []Value) ([]elementKey, error) {
+	keys := make([]elementKey, len(elems))
+	for i, e := range elems {
+		if e.Kind() != KindObject {
+			continue
+		}
+		_, byKey, _, err := objectMembers(e)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := byKey[df.opts.Key]
+		if !ok {
+			continue
+		}
+		h := fnv.New64a()
+		if err := hashValue(h, v); err != nil {
+			return nil, err
+		}
+		keys[i] = elementKey{value: v, hash: h.Sum64(), ok: true}
+	}
+	return keys, nil
+}
+
+func hashElements(elems []Value) ([]uint64, error) {
+	hashes := make([]uint64, len(elems))
+	for i, e := range elems {
+		h := fnv.New64a()
+		if err := hashValue(h, e); err != nil {
+			return nil, err
+		}
+		hashes[i] = h.Sum64()
+	}
+	return hashes, nil
+}
+
+// JSONPatch returns changes as a JSON Patch (RFC 6902) document
+func JSONPatch(changes []Change) ([]byte, error) {
+	w := NewAppendWriter(nil)
+	w.BeginArray()
+	for _, c := range changes {
+		w.BeginObject()
+		w.Key("op")
+		switch c.Kind {
+		case ChangeAdded:
+			w.String("add")
+		case ChangeRemoved:
+			w.String("remove")
+		case ChangeMoved:
+			w.String("move")
+			w.Key("from")
+			w.String(c.From.String())
+		default:
+			w.String("replace")
+		}
+		w.Key("path")
+		w.String(c.Path.String())
+		if c.Kind != ChangeRemoved && c.Kind != ChangeMoved {
+			v, err := Compact(nil, c.New)
+			if err != nil {
+				return nil, err
+			}
+			w.Key("value")
+			w.Raw(v)
+		}
+		w.EndObject()
+	}
+	w.EndArray()
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
@@ -0,0 +1,365 @@
+package gojson
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		opts     DiffOptions
+		expected []string // "kind path", or "moved path from"
+		wantErr  error
+	}{
+		{
+			name: "equal",
+			a:    `{"a": [1, 2.0], "b": "x"}`,
+			b:    `{"b":"x","a":[1,2]}`,
+		},
+		{
+			name:     "top level",
+			a:        `1`,
+			b:        `"1"`,
+			expected: []string{"replaced "},
+		},
+		{
+			name:     "members",
+			a:        `{"same": 1, "changed": 1, "removed": 1, "kind": {}}`,
+			b:        `{"same": 1, "changed": 2, "kind": [], "added/~": 1}`,
+			expected: []string{"replaced /changed", "removed /removed", "replaced /kind", "added /added~1~0"},
+		},
+		{
+			name:     "repeated key",
+			a:        `{"x": {"a": 1, "a": 2}}`,
+			b:        `{"x": {"a": 2}}`,
+			expected: []string{"replaced /x"},
+		},
+		{
+			name:     "repeated key top level",
+			a:        `{"a": 1, "a": 2}`,
+			b:        `{"a": 2}`,
+			expected: []string{"replaced "},
+		},
+		{
+			name:     "nested",
+			a:        `{"a": {"b": [1, {"c": true}]}}`,
+			b:        `{"a": {"b": [1, {"c": false}]}}`,
+			expected: []string{"replaced /a/b/1/c"},
+		},
+		{
+			name:     "by index",
+			a:        `[1, 2, 3, 4]`,
+			b:        `[0, 1, 2]`,
+			expected: []string{"replaced /0", "replaced /1", "replaced /2", "removed /3"},
+		},
+		{
+			name:     "by index grows",
+			a:        `[1]`,
+			b:        `[1, 2, 3]`,
+			expected: []string{"added /1", "added /2"},
+		},
+		{
+			name:     "lcs insert at front",
+			a:        `[1, 2, 3, 4]`,
+			b:        `[0, 1, 2, 3]`,
+			opts:     DiffOptions{Arrays: ArrayMatchLCS},
+			expected: []string{"added /0", "removed /4"},
+		},
+		{
+			name:     "lcs changed in place",
+			a:        `[1, {"a": 1}, 3]`,
+			b:        `[1, {"a": 2}, 3, 4]`,
+			opts:     DiffOptions{Arrays: ArrayMatchLCS},
+			expected: []string{"replaced /1/a", "added /3"},
+		},
+		{
+			name:     "lcs swap",
+			a:        `["x", "y"]`,
+			b:        `["y", "x"]`,
+			opts:     DiffOptions{Arrays: ArrayMatchLCS},
+			expected: []string{"removed /0", "added /1"},
+		},
+		{
+			name:     "lcs between matches",
+			a:        `[1, 2, 3, 4, 5, 6]`,
+			b:        `[0, 2, 9, 4, 6, 7]`,
+			opts:     DiffOptions{Arrays: ArrayMatchLCS},
+			expected: []string{"replaced /0", "replaced /2", "removed /4", "added /5"},
+		},
+		{
+			name:     "lcs numbers by value",
+			a:        `[1.0, 2, 3]`,
+			b:        `[1, 3]`,
+			opts:     DiffOptions{Arrays: ArrayMatchLCS},
+			expected: []string{"removed /1"},
+		},
+		{
+			name:     "by key",
+			a:        `[{"_id": 1, "v": "a"}, {"_id": 2, "v": "b"}, {"_id": 3}, "x"]`,
+			b:        `[{"_id": 3}, {"_id": 1.0, "v": "A"}, {"_id": 4}]`,
+			opts:     DiffOptions{Arrays: ArrayMatchKey, Key: "_id"},
+			expected: []string{"removed /3", "removed /1", "moved /0 from /1", "replaced /1/v", "added /2"},
+		},
+		{
+			name:     "by key reordered",
+			a:        `[{"id": 1, "v": 1}, {"id": 2}, {"id": 3}]`,
+			b:        `[{"id": 3}, {"id": 1, "v": 2}, {"id": 4}]`,
+			opts:     DiffOptions{Arrays: ArrayMatchKey, Key: "id"},
+			expected: []string{"removed /1", "moved /0 from /1", "replaced /1/v", "added /2"},
+		},
+		{
+			name:     "by key rotated",
+			a:        `[{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}]`,
+			b:        `[{"id": 4}, {"id": 3}, {"id": 5}, {"id": 1}, {"id": 2}]`,
+			opts:     DiffOptions{Arrays: ArrayMatchKey, Key: "id"},
+			expected: []string{"moved /0 from /3", "moved /1 from /3", "added /2"},
+		},
+		{
+			name:    "invalid",
+			a:       `{}`,
+			b:       `{"a":}`,
+			wantErr: ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := tt.opts.Diff([]byte(tt.a), []byte(tt.b))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var actual []string
+			for _, c := range changes {
+				s := c.Kind.String() + " " + c.Path.String()
+				if c.Kind == ChangeMoved {
+					s += " from " + c.From.String()
+				}
+				actual = append(actual, s)
+			}
+			if strings.Join(actual, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected\n%s\ngot\n%s", strings.Join(tt.expected, "\n"), strings.Join(actual, "\n"))
+			}
+
+			patched, err := applyPatch(t, tt.a, changes)
+			if err != nil {
+				t.Fatalf("applying the changes: %v", err)
+			}
+			if !Equal(patched, []byte(tt.b)) {
+				t.Errorf("applying the changes gave %s", patched)
+			}
+		})
+	}
+}
+
+func TestDiffLCSMemory(t *testing.T) {
+	// a table of the lcs of every a[i:] and b[j:] would be 128MB
+	const n = 4000
+	var a, b []string
+	for i := 0; i < n; i++ {
+		a = append(a, strconv.Itoa(i))
+		b = append(b, strconv.Itoa(n-i))
+	}
+	aj, bj := "["+strings.Join(a, ",")+"]", "["+strings.Join(b, ",")+"]"
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	changes, err := DiffOptions{Arrays: ArrayMatchLCS}.Diff([]byte(aj), []byte(bj))
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 16<<20 {
+		t.Errorf("expected less than 16MB allocated, got %dMB", alloc>>20)
+	}
+
+	patched, err := applyPatch(t, aj, changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(patched, []byte(bj)) {
+		t.Error("applying the changes didn't give b")
+	}
+}
+
+func TestDiffHugeExponent(t *testing.T) {
+	// too big for a float64, so applyPatch can't check these
+	changes, err := DiffOptions{Arrays: ArrayMatchLCS}.Diff([]byte(`[1e9999999999, 1]`), []byte(`[1, 10e9999999998]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Kind != ChangeRemoved || changes[1].Kind != ChangeAdded {
+		t.Errorf("expected the first element moved to the end, got %v", changes)
+	}
+}
+
+func TestDiffExample(t *testing.T) {
+	a := readFile(t, "example.json")
+	b := []byte(strings.NewReplacer(
+		`"age": 31`, `"age": 32`,
+		`"isActive": true`, `"isActive": "yes"`,
+	).Replace(string(a)))
+
+	for _, opts := range []DiffOptions{{}, {Arrays: ArrayMatchLCS}, {Arrays: ArrayMatchKey, Key: "_id"}} {
+		changes, err := opts.Diff(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) == 0 {
+			t.Fatal("expected changes")
+		}
+		for _, c := range changes {
+			if c.Kind != ChangeReplaced || len(c.Path) != 2 {
+				t.Errorf("unexpected change %s %s", c.Kind, c.Path)
+			}
+		}
+		patched, err := applyPatch(t, string(a), changes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Equal(patched, b) {
+			t.Error("applying the changes didn't give b")
+		}
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	changes, err := Diff([]byte(`{"a": 1, "b": [1], "c": {"d": null}}`), []byte(`{"b": [1, { "x" : 2 }], "c": {"d": "/"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := JSONPatch(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"op":"remove","path":"/a"},{"op":"add","path":"/b/1","value":{"x":2}},{"op":"replace","path":"/c/d","value":"/"}]`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	changes, err = DiffOptions{Arrays: ArrayMatchKey, Key: "id"}.Diff([]byte(`[{"id": 1}, {"id": 2}]`), []byte(`[{"id": 2}, {"id": 1}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err = JSONPatch(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `[{"op":"move","from":"/1","path":"/0"}]`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	empty, err := JSONPatch(nil)
+	if err != nil || string(empty) != `[]` {
+		t.Errorf("expected [], got %s, %v", empty, err)
+	}
+}
+
+// applyPatch applies changes to the document a the way JSON Patch
+// would, one after another
+func applyPatch(t *testing.T, a string, changes []Change) ([]byte, error) {
+	t.Helper()
+
+	var doc interface{}
+	if err := Unmarshal([]byte(a), &doc); err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		var value interface{}
+		var err error
+		if c.Kind == ChangeMoved {
+			// a move is removing the value at From and adding it at Path
+			if value, err = lookup(doc, c.From); err != nil {
+				return nil, err
+			}
+			if doc, err = applyChange(doc, ChangeRemoved, c.From, nil); err != nil {
+				return nil, err
+			}
+			if doc, err = applyChange(doc, ChangeAdded, c.Path, value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if c.New != nil {
+			if err := Unmarshal(c.New, &value); err != nil {
+				return nil, err
+			}
+		}
+		if doc, err = applyChange(doc, c.Kind, c.Path, value); err != nil {
+			return nil, err
+		}
+	}
+	return Marshal(doc)
+}
+
+func lookup(doc interface{}, path Pointer) (interface{}, error) {
+	for _, tok := range path {
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			doc = d[tok]
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(d) {
+				return nil, errors.New("bad index " + tok)
+			}
+			doc = d[i]
+		default:
+			return nil, errors.New("can't index into a literal")
+		}
+	}
+	return doc, nil
+}
+
+func applyChange(doc interface{}, kind ChangeKind, path Pointer, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	tok, rest := path[0], path[1:]
+
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		if len(rest) > 0 {
+			v, err := applyChange(d[tok], kind, rest, value)
+			d[tok] = v
+			return d, err
+		}
+		if kind == ChangeRemoved {
+			delete(d, tok)
+		} else {
+			d[tok] = value
+		}
+		return d, nil
+
+	case []interface{}:
+		i, err := strconv.Atoi(tok)
+		if err != nil || i < 0 || i > len(d) || i == len(d) && kind != ChangeAdded {
+			return nil, errors.New("bad index " + tok)
+		}
+		if len(rest) > 0 {
+			d[i], err = applyChange(d[i], kind, rest, value)
+			return d, err
+		}
+		switch kind {
+		case ChangeAdded:
+			d = append(d[:i], append([]interface{}{value}, d[i:]...)...)
+		case ChangeRemoved:
+			d = append(d[:i], d[i+1:]...)
+		default:
+			d[i] = value
+		}
+		return d, nil
+	}
+	return nil, errors.New("can't index into a literal")
+}
@@ -0,0 +1,171 @@
+package gojson
+
+// Kind is the type of a json value
+type Kind int
+
+const (
+	KindInvalid Kind = iota
+	KindObject
+	KindArray
+	KindString
+	KindNumber
+	KindBool
+	KindNull
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindObject:
+		return "Object"
+	case KindArray:
+		return "Array"
+	case KindString:
+		return "String"
+	case KindNumber:
+		return "Number"
+	case KindBool:
+		return "Bool"
+	case KindNull:
+		return "Null"
+	}
+	return "Invalid"
+}
+
+// tokenKind returns the Kind of the value a Token starts
+func tokenKind(k TokenKind) Kind {
+	switch k {
+	case TokenBeginObject:
+		return KindObject
+	case TokenBeginArray:
+		return KindArray
+	case TokenString:
+		return KindString
+	case TokenNumber:
+		return KindNumber
+	case TokenTrue, TokenFalse:
+		return KindBool
+	case TokenNull:
+		return KindNull
+	}
+	return KindInvalid
+}
+
+// Node is a value in a document built by ParseNode. Raw points into
+// the input and Offset is where Raw starts in it.
+type Node struct {
+	Kind   Kind
+	Raw    Value
+	Offset int
+
+	// Members of an object in the order they appear in the input,
+	// duplicate keys included
+	Members []Member
+
+	// Elements of an array
+	Elements []Node
+}
+
+// Member is a key and value of an object. Key is quoted, as it appears
+// in the input.
+type Member struct {
+	Key   String
+	Value Node
+}
+
+// ParseNode consumes a single json document from the front of b, the
+// same as ParseJSON, and builds a tree of it. It returns the number of
+// bytes consumed.
+//
+// ParseNode enforces DefaultParseOptions.
+func ParseNode(b []byte) (Node, int, error) {
+	return DefaultParseOptions.ParseNode(b)
+}
+
+// ParseNode is ParseNode enforcing o
+func (o ParseOptions) ParseNode(b []byte) (Node, int, error) {
+	var l Lexer
+	l.stack = l.small[:0]
+	l.opts = o
+	l.Reset(b)
+
+	t := treeBuilder{b: b}
+	c, err := parseWithHandler(&l, &t)
+	if err != nil {
+		return Node{}, 0, err
+	}
+	return t.root, c, nil
+}
+
+// treeBuilder is a Handler that builds the Nodes of a document
+type treeBuilder struct {
+	b    []byte
+	root Node
+
+	// the objects and arrays being built, each with the key it will be
+	// added to its parent under
+	stack []Node
+	keys  []String
+	key   String
+}
+
+func (t *treeBuilder) OnObjectStart(offset int) error {
+	t.begin(KindObject, offset)
+	return nil
+}
+
+func (t *treeBuilder) OnObjectEnd(offset int) error {
+	t.end(offset)
+	return nil
+}
+
+func (t *treeBuilder) OnArrayStart(offset int) error {
+	t.begin(KindArray, offset)
+	return nil
+}
+
+func (t *treeBuilder) OnArrayEnd(offset int) error {
+	t.end(offset)
+	return nil
+}
+
+func (t *treeBuilder) OnKey(key String, _ int) error {
+	t.key = key
+	return nil
+}
+
+func (t *treeBuilder) OnValue(v Value, kind TokenKind, offset int) error {
+	t.add(Node{Kind: tokenKind(kind), Raw: v, Offset: offset})
+	return nil
+}
+
+func (t *treeBuilder) begin(kind Kind, offset int) {
+	t.stack = append(t.stack, Node{Kind: kind, Offset: offset})
+	t.keys = append(t.keys, t.key)
+}
+
+// end finishes the object or array closed at b[offset]
+func (t *treeBuilder) end(offset int) {
+	n := t.stack[len(t.stack)-1]
+	n.Raw = t.b[n.Offset : offset+1]
+	t.key = t.keys[len(t.keys)-1]
+
+	t.stack = t.stack[:len(t.stack)-1]
+	t.keys = t.keys[:len(t.keys)-1]
+	t.add(n)
+}
+
+// add adds n to the object or array being built, under the last key
+// seen if that's an object
+func (t *treeBuilder) add(n Node) {
+	if len(t.stack) == 0 {
+		t.root = n
+		return
+	}
+
+	parent := &t.stack[len(t.stack)-1]
+	if parent.Kind == KindObject {
+		parent.Members = append(parent.Members, Member{Key: t.key, Value: n})
+	} else {
+		parent.Elements = append(parent.Elements, n)
+	}
+}
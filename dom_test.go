@@ -0,0 +1,155 @@
+package gojson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dump writes n as "Kind Raw@Offset", one node per line, with each
+// member's key before its value and children indented
+func dump(out *strings.Builder, n Node, indent string) {
+	raw := string(n.Raw)
+	if n.Kind == KindObject || n.Kind == KindArray {
+		raw = raw[:1]
+	}
+	fmt.Fprintf(out, "%s %s@%d\n", n.Kind, raw, n.Offset)
+
+	for _, m := range n.Members {
+		fmt.Fprintf(out, "%s  %s: ", indent, string(m.Key))
+		dump(out, m.Value, indent+"  ")
+	}
+	for _, e := range n.Elements {
+		fmt.Fprintf(out, "%s  ", indent)
+		dump(out, e, indent+"  ")
+	}
+}
+
+func TestParseNode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		consumed int
+		wantErr  error
+	}{
+		{
+			name:     "scalar",
+			input:    ` "abc" `,
+			expected: "String \"abc\"@1\n",
+			consumed: 7,
+		},
+		{
+			name:  "object",
+			input: `{"a": [1, true, null], "b": {"c": -2.5e1}}`,
+			expected: "Object {@0\n" +
+				"  \"a\": Array [@6\n" +
+				"    Number 1@7\n" +
+				"    Bool true@10\n" +
+				"    Null null@16\n" +
+				"  \"b\": Object {@28\n" +
+				"    \"c\": Number -2.5e1@34\n",
+			consumed: 42,
+		},
+		{
+			name:  "members keep their order and duplicates",
+			input: `{"z": 1, "a": 2, "z": 3}`,
+			expected: "Object {@0\n" +
+				"  \"z\": Number 1@6\n" +
+				"  \"a\": Number 2@14\n" +
+				"  \"z\": Number 3@22\n",
+			consumed: 24,
+		},
+		{
+			name:  "empty containers",
+			input: `[{}, [], ""]`,
+			expected: "Array [@0\n" +
+				"  Object {@1\n" +
+				"  Array [@5\n" +
+				"  String \"\"@9\n",
+			consumed: 12,
+		},
+		{
+			name:     "trailing data is left",
+			input:    `[1] [2]`,
+			expected: "Array [@0\n  Number 1@1\n",
+			consumed: 4,
+		},
+		{
+			name:    "missing close",
+			input:   `{"a": [1, 2}`,
+			wantErr: ErrInvalidArrayClose,
+		},
+		{
+			name:    "empty input",
+			input:   ``,
+			wantErr: ErrEOF,
+		},
+	}
+
+	for _, tc := range tests {
+		n, c, err := ParseNode([]byte(tc.input))
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: expected %v: got %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		var out strings.Builder
+		dump(&out, n, "")
+		if out.String() != tc.expected {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", tc.name, tc.expected, out.String())
+		}
+		if c != tc.consumed {
+			t.Errorf("%s: expected to consume %d: got %d", tc.name, tc.consumed, c)
+		}
+	}
+}
+
+func TestParseNodeRaw(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	n, _, err := ParseNode(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every node's Raw is the slice of the input at its Offset
+	var check func(n Node)
+	check = func(n Node) {
+		if string(example[n.Offset:n.Offset+len(n.Raw)]) != string(n.Raw) {
+			t.Errorf("%s@%d: Raw is not at its Offset", n.Kind, n.Offset)
+		}
+		if _, c, err := ParseValue(n.Raw); err != nil || c != len(n.Raw) {
+			t.Errorf("%s@%d: Raw is not a single value: %v", n.Kind, n.Offset, err)
+		}
+		for _, m := range n.Members {
+			check(m.Value)
+		}
+		for _, e := range n.Elements {
+			check(e)
+		}
+	}
+	check(n)
+
+	name := n.Elements[3].Members[8]
+	if name.Key.String() != "name" {
+		t.Fatalf("expected the name member: got %s", name.Key)
+	}
+	if first := name.Value.Members[0].Value.Raw; string(first) != `"Hayden"` {
+		t.Errorf("expected \"Hayden\": got %s", first)
+	}
+}
+
+func TestParseNodeOptions(t *testing.T) {
+	_, _, err := ParseOptions{MaxDepth: 1}.ParseNode([]byte(`[[]]`))
+	if !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Errorf("expected %v: got %v", ErrMaxDepthExceeded, err)
+	}
+}
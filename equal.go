@@ -0,0 +1,229 @@
+package gojson
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"sort"
+)
+
+// Equal reports whether the json documents a and b hold the same
+// value. Whitespace and the order of object members don't matter,
+// strings are compared once unescaped and numbers by their exact
+// value, so 1.0, 1e0 and 10E-1 are all equal. Objects with a key more
+// than once are only equal if the duplicates are in the same order.
+// An invalid document isn't equal to anything.
+func Equal(a, b []byte) bool {
+	if ValidateJSON(a) != nil || ValidateJSON(b) != nil {
+		return false
+	}
+	_, c := ParseWhitespace(a)
+	_, d := ParseWhitespace(b)
+	eq, err := equal(a[c:], b[d:])
+	return eq && err == nil
+}
+
+// Hash returns a 64-bit hash of the json document b which is the same
+// for documents that are Equal, e.g. for deduplicating them or keying a
+// cache. It is the same across runs and platforms.
+func Hash(b []byte) (uint64, error) {
+	if err := ValidateJSON(b); err != nil {
+		return 0, err
+	}
+	_, c := ParseWhitespace(b)
+
+	h := fnv.New64a()
+	if err := hashValue(h, b[c:]); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+// equal compares the valid values at the front of a and b
+func equal(a, b Value) (bool, error) {
+	kind := a.Kind()
+	if kind != b.Kind() {
+		return false, nil
+	}
+
+	switch kind {
+	case KindObject:
+		am, err := sortedMembers(a)
+		if err != nil {
+			return false, err
+		}
+		bm, err := sortedMembers(b)
+		if err != nil {
+			return false, err
+		}
+		if len(am) != len(bm) {
+			return false, nil
+		}
+		for i := range am {
+			if !bytes.Equal(am[i].key, bm[i].key) {
+				return false, nil
+			}
+			if eq, err := equal(am[i].value, bm[i].value); !eq || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case KindArray:
+		ae, be := elements(a), elements(b)
+		if len(ae) != len(be) {
+			return false, nil
+		}
+		for i := range ae {
+			if eq, err := equal(ae[i], be[i]); !eq || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case KindString:
+		as, err := unquoteValue(a)
+		if err != nil {
+			return false, err
+		}
+		bs, err := unquoteValue(b)
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(as, bs), nil
+
+	case KindNumber:
+		an, err := normalizedValue(a)
+		if err != nil {
+			return false, err
+		}
+		bn, err := normalizedValue(b)
+		if err != nil {
+			return false, err
+		}
+		return an == bn, nil
+	}
+
+	// true, false or null
+	return a[0] == b[0], nil
+}
+
+// hashValue writes the valid value at the front of v to h in a form
+// that is the same for equal values. Each value starts with a byte for
+// its kind, '{', '[', '"', '0', 't', 'f' or 'n', and variable length
+// parts are prefixed with their length so different values can't run
+// together into the same bytes.
+func hashValue(h hash.Hash64, v Value) error {
+	switch v.Kind() {
+	case KindObject:
+		members, err := sortedMembers(v)
+		if err != nil {
+			return err
+		}
+		h.Write([]byte{'{'})
+		hashLen(h, len(members))
+		for _, m := range members {
+			hashLen(h, len(m.key))
+			h.Write(m.key)
+			if err := hashValue(h, m.value); err != nil {
+				return err
+			}
+		}
+
+	case KindArray:
+		elems := elements(v)
+		h.Write([]byte{'['})
+		hashLen(h, len(elems))
+		for _, e := range elems {
+			if err := hashValue(h, e); err != nil {
+				return err
+			}
+		}
+
+	case KindString:
+		s, err := unquoteValue(v)
+		if err != nil {
+			return err
+		}
+		h.Write([]byte{'"'})
+		hashLen(h, len(s))
+		h.Write(s)
+
+	case KindNumber:
+		s, err := normalizedValue(v) // the same for equal numbers
+		if err != nil {
+			return err
+		}
+		h.Write([]byte{'0'})
+		hashLen(h, len(s))
+		h.Write([]byte(s))
+
+	default:
+		h.Write(v[:1]) // 't', 'f' or 'n'
+	}
+	return nil
+}
+
+func hashLen(h hash.Hash64, n int) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(n))])
+}
+
+// member is an object member with its key unescaped
+type member struct {
+	key   []byte
+	value Value
+}
+
+// unquotedMembers returns the members of the valid object v in order,
+// duplicate keys included
+func unquotedMembers(v Value) ([]member, error) {
+	var members []member
+	var err error
+	eachMember(v, func(key String, val Value, c int) {
+		if err != nil {
+			return
+		}
+		var k []byte
+		if k, err = key.Unquote(); err == nil {
+			members = append(members, member{k, val})
+		}
+	})
+	return members, err
+}
+
+// sortedMembers is unquotedMembers sorted by key, keeping duplicate
+// keys in the order they appear
+func sortedMembers(v Value) ([]member, error) {
+	members, err := unquotedMembers(v)
+	sort.SliceStable(members, func(i, j int) bool {
+		return bytes.Compare(members[i].key, members[j].key) < 0
+	})
+	return members, err
+}
+
+// elements returns the elements of the valid array v
+func elements(v Value) []Value {
+	var elems []Value
+	eachElement(v, func(e Value, c int) {
+		elems = append(elems, e)
+	})
+	return elems
+}
+
+func unquoteValue(v Value) ([]byte, error) {
+	s, _, err := ParseString(v)
+	if err != nil {
+		return nil, err
+	}
+	return s.Unquote()
+}
+
+func normalizedValue(v Value) (string, error) {
+	n, _, err := ParseNumber(v)
+	if err != nil {
+		return "", err
+	}
+	return n.normalized()
+}
@@ -0,0 +1,94 @@
+package gojson
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{name: "identical", a: `{"a":[1,2]}`, b: `{"a":[1,2]}`, expected: true},
+		{name: "whitespace", a: " {\"a\" : [ 1 ,2 ] }\n", b: `{"a":[1,2]}`, expected: true},
+		{name: "key order", a: `{"a":1,"b":{"c":2,"d":3}}`, b: `{"b":{"d":3,"c":2},"a":1}`, expected: true},
+		{name: "element order matters", a: `[1,2]`, b: `[2,1]`},
+		{name: "numbers by value", a: `[1.0, 1e0, 10E-1, 0.5, -0, 1e400]`, b: `[1, 1, 1, 5e-1, 0, 10e399]`, expected: true},
+		{name: "huge exponents", a: `[1e9999999999, -2.50E-99999999999]`, b: `[10e9999999998, -25e-100000000000]`, expected: true},
+		{name: "huge exponents differ", a: `1e9999999999`, b: `1e9999999998`},
+		{name: "zero with a huge exponent", a: `0e9999999999`, b: `-0.0`, expected: true},
+		{name: "numbers differ", a: `0.1`, b: `0.10000000000000001`},
+		{name: "large integers differ", a: `9007199254740993`, b: `9007199254740992`},
+		{name: "escapes", a: `{"a":"é\/\n"}`, b: "{\"a\":\"é/\\n\"}", expected: true},
+		{name: "strings differ", a: `"a"`, b: `"A"`},
+		{name: "kinds differ", a: `"1"`, b: `1`},
+		{name: "literals", a: `[true,false,null]`, b: `[true,false,null]`, expected: true},
+		{name: "true and false", a: `true`, b: `false`},
+		{name: "missing member", a: `{"a":1}`, b: `{"a":1,"b":2}`},
+		{name: "member renamed", a: `{"a":1}`, b: `{"b":1}`},
+		{name: "longer array", a: `[1]`, b: `[1,1]`},
+		{name: "duplicate keys in order", a: `{"a":1,"b":0,"a":2}`, b: `{"b":0,"a":1,"a":2}`, expected: true},
+		{name: "duplicate keys out of order", a: `{"a":1,"a":2}`, b: `{"a":2,"a":1}`},
+		{name: "invalid", a: `{"a":}`, b: `{"a":}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Equal([]byte(tt.a), []byte(tt.b)); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+			if actual := Equal([]byte(tt.b), []byte(tt.a)); actual != tt.expected {
+				t.Errorf("reversed: expected %v, got %v", tt.expected, actual)
+			}
+
+			ha, erra := Hash([]byte(tt.a))
+			hb, errb := Hash([]byte(tt.b))
+			if erra != nil || errb != nil {
+				if tt.expected {
+					t.Fatalf("unexpected errors: %v, %v", erra, errb)
+				}
+				return
+			}
+			if tt.expected && ha != hb {
+				t.Errorf("equal documents hash differently: %x and %x", ha, hb)
+			}
+			if !tt.expected && ha == hb {
+				t.Errorf("unequal documents hash the same: %x", ha)
+			}
+		})
+	}
+}
+
+// exampleHash is the Hash of example.json. It must not change, hashes
+// may have been stored as keys.
+const exampleHash = 0xcfc5a50064c03f
+
+func TestHash(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	h, err := Hash(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := Compact(nil, example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hc, _ := Hash(compact); hc != h {
+		t.Errorf("compacting changed the hash: %x and %x", h, hc)
+	}
+
+	if h != exampleHash {
+		t.Errorf("expected %#x, got %#x", exampleHash, h)
+	}
+
+	if _, err := Hash([]byte(`[1,]`)); err == nil {
+		t.Error("expected an error for invalid json")
+	}
+	huge := []byte(`1e9999999999`)
+	if !Equal(huge, huge) {
+		t.Errorf("%s isn't equal to itself", huge)
+	}
+	if _, err := Hash(huge); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
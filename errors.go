@@ -0,0 +1,85 @@
+package gojson
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// SyntaxError describes where in the input a Parse function failed.
+//
+// Offset, Line and Column are relative to the slice handed to the
+// Parse function that returned the error. Line and Column are 1-based
+// and Column counts bytes, not runes.
+//
+// Err is one of the Err* variables in gojson.go, so callers can keep
+// using errors.Is(err, ErrInvalidObjectClose) and friends.
+type SyntaxError struct {
+	Offset int
+	Line   int
+	Column int
+
+	// Expected is the grammar production (e.g. "object", "string",
+	// "escape") that was being parsed when the error occurred
+	Expected string
+
+	// Byte is the offending byte, or 0 when the input ended early
+	Byte byte
+
+	// EOF is whether the input ended early (i.e. Offset == len(input)),
+	// which tells it apart from a NUL Byte
+	EOF bool
+
+	Err error
+}
+
+func (e *SyntaxError) Error() string {
+	found := "end of input"
+	if !e.EOF {
+		found = fmt.Sprintf("%q", e.Byte)
+	}
+	return fmt.Sprintf("%d:%d: %s: expecting %s, found %s (offset %d)", e.Line, e.Column, e.Err, e.Expected, found, e.Offset)
+}
+
+func (e *SyntaxError) Unwrap() error { return e.Err }
+
+// syntaxError returns a *SyntaxError for err positioned at b[off]
+func syntaxError(b []byte, off int, expected string, err error) error {
+	e := &SyntaxError{
+		Line:     1,
+		Column:   1,
+		Expected: expected,
+		Err:      err,
+	}
+	if off < len(b) {
+		e.Byte = b[off]
+	} else {
+		e.EOF = true
+	}
+	return shiftError(b, off, e)
+}
+
+// shiftError repositions an error returned from parsing b[c:] so that
+// it is relative to b. Errors that are not a *SyntaxError are returned
+// unchanged.
+//
+// Only b[:c] is scanned, so shifting an error up through every level
+// of a nested document costs no more than the length of the input.
+func shiftError(b []byte, c int, err error) error {
+	e, ok := err.(*SyntaxError)
+	if !ok || c == 0 {
+		return err
+	}
+
+	prefix := b[:c]
+	e.Offset += c
+
+	nl := bytes.Count(prefix, []byte{'\n'})
+	if e.Line == 1 {
+		// the error was on the first line of b[c:] so its column
+		// continues from whatever line prefix ends on
+		e.Column += c - (bytes.LastIndexByte(prefix, '\n') + 1)
+	}
+	e.Line += nl
+
+	return e
+}
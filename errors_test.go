@@ -0,0 +1,192 @@
+package gojson
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readFile(t testing.TB, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+
+		// expected
+		err      error
+		offset   int
+		line     int
+		column   int
+		expected string
+		b        byte
+		eof      bool
+	}{
+		{
+			name:     "empty input",
+			input:    []byte(""),
+			err:      ErrEOF,
+			offset:   0,
+			line:     1,
+			column:   1,
+			expected: "value",
+			eof:      true,
+		},
+		{
+			name:     "missing object close",
+			input:    []byte(`{"a": 1 ]`),
+			err:      ErrInvalidObjectClose,
+			offset:   8,
+			line:     1,
+			column:   9,
+			expected: "object",
+			b:        ']',
+		},
+		{
+			name:     "missing member separator on a later line",
+			input:    []byte("{\n  \"a\": 1,\n  \"b\"  2\n}"),
+			err:      ErrInvalidMemberMissingSep,
+			offset:   19,
+			line:     3,
+			column:   8,
+			expected: "member",
+			b:        '2',
+		},
+		{
+			name:     "missing member separator in first member",
+			input:    []byte("[\n\n  {\"b\"  2}\n]"),
+			err:      ErrInvalidMemberMissingSep,
+			offset:   11,
+			line:     3,
+			column:   9,
+			expected: "member",
+			b:        '2',
+		},
+		{
+			name:     "unterminated string at end of input",
+			input:    []byte("[\r\n\"abc"),
+			err:      ErrInvalidStringClose,
+			offset:   7,
+			line:     2,
+			column:   5,
+			expected: "string",
+			eof:      true,
+		},
+		{
+			name:     "misspelled null",
+			input:    []byte("  \n\t  nope"),
+			err:      ErrInvalidNull,
+			offset:   7,
+			line:     2,
+			column:   5,
+			expected: "null",
+			b:        'o',
+		},
+		{
+			name:     "unsupported value",
+			input:    []byte(`{"a": @}`),
+			err:      ErrUnsupported,
+			offset:   6,
+			line:     1,
+			column:   7,
+			expected: "value",
+			b:        '@',
+		},
+		{
+			name:     "nul byte",
+			input:    []byte("[\x00]"),
+			err:      ErrUnsupported,
+			offset:   1,
+			line:     1,
+			column:   2,
+			expected: "value",
+			b:        0,
+		},
+		{
+			name:     "trailing comma in array",
+			input:    []byte(`[1, 2,]`),
+			err:      ErrUnsupported,
+			offset:   6,
+			line:     1,
+			column:   7,
+			expected: "value",
+			b:        ']',
+		},
+		{
+			name:     "invalid escape deep inside nested objects",
+			input:    []byte(`{"a": [1, {"b": {"c": "ok", "d": "bad \x"}}]}`),
+			err:      ErrInvalidEscape,
+			offset:   39,
+			line:     1,
+			column:   40,
+			expected: "escape",
+			b:        'x',
+		},
+		{
+			name:     "trailing data",
+			input:    []byte("{\"a\":1}\n  garbage"),
+			err:      ErrTrailingData,
+			offset:   10,
+			line:     2,
+			column:   3,
+			expected: "end of input",
+			b:        'g',
+		},
+		{
+			name:     "badExample.json",
+			input:    readFile(t, "badExample.json"),
+			err:      ErrInvalidEscape,
+			offset:   340,
+			line:     15,
+			column:   24,
+			expected: "escape",
+			b:        'A',
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			err := ValidateJSON(tc.input)
+
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error: wanted %v got %v", tc.err, err)
+			}
+
+			var se *SyntaxError
+			if !errors.As(err, &se) {
+				t.Fatalf("expecting *SyntaxError but got %T", err)
+			}
+
+			if se.Offset != tc.offset {
+				t.Errorf("unexpected offset: wanted %d got %d", tc.offset, se.Offset)
+			}
+			if se.Line != tc.line || se.Column != tc.column {
+				t.Errorf("unexpected position: wanted %d:%d got %d:%d", tc.line, tc.column, se.Line, se.Column)
+			}
+			if se.Expected != tc.expected {
+				t.Errorf("unexpected production: wanted %q got %q", tc.expected, se.Expected)
+			}
+			if se.Byte != tc.b || se.EOF != tc.eof {
+				t.Errorf("unexpected byte: wanted %q (eof %v) got %q (eof %v)", tc.b, tc.eof, se.Byte, se.EOF)
+			}
+
+			found := "found end of input"
+			if !tc.eof {
+				found = fmt.Sprintf("found %q", tc.b)
+			}
+			if !strings.Contains(se.Error(), found) {
+				t.Errorf("expected %q in %q", found, se.Error())
+			}
+		})
+	}
+}
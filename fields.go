@@ -0,0 +1,186 @@
+package gojson
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field is a struct field as json sees it, following encoding/json's
+// rules for tags and embedded structs
+type field struct {
+	name  string
+	index []int // for reflect.Value.FieldByIndex
+	typ   reflect.Type
+
+	tagged    bool // the name came from a tag
+	omitEmpty bool
+	quoted    bool // the ",string" option, on a type it applies to
+}
+
+type structFields struct {
+	list   []field
+	byName map[string]int // index into list
+}
+
+// find returns the field named name, or the first one named the same
+// ignoring case as encoding/json does
+func (s *structFields) find(name []byte) *field {
+	if i, ok := s.byName[string(name)]; ok {
+		return &s.list[i]
+	}
+	for i := range s.list {
+		if strings.EqualFold(s.list[i].name, string(name)) {
+			return &s.list[i]
+		}
+	}
+	return nil
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedFields returns the fields of the struct type t
+func cachedFields(t reflect.Type) *structFields {
+	if s, ok := fieldCache.Load(t); ok {
+		return s.(*structFields)
+	}
+	s, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return s.(*structFields)
+}
+
+// parseTag splits a json tag into its name and options
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+func hasOption(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// typeFields walks t breadth first so that fields of embedded structs
+// are promoted, and drops the ones hidden by a shallower field of the
+// same name
+func typeFields(t reflect.Type) *structFields {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []field
+	next := []embedded{{typ: t}}
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if sf.Anonymous {
+					// an unexported embedded struct can still promote
+					// exported fields
+					if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if sf.PkgPath != "" {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				index := append(append([]int{}, e.index...), i)
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: ft, index: index})
+					continue
+				}
+
+				f := field{
+					name:      name,
+					index:     index,
+					typ:       sf.Type,
+					tagged:    name != "",
+					omitEmpty: hasOption(opts, "omitempty"),
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				if hasOption(opts, "string") {
+					switch ft.Kind() {
+					case reflect.Bool,
+						reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+						reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+						reflect.Float32, reflect.Float64,
+						reflect.String:
+						f.quoted = true
+					}
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	// of the fields sharing a name the shallowest wins, then the one
+	// with a tag. If that leaves more than one they all lose.
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+	kept := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		dominant := fields[i]
+		ambiguous := j-i > 1 &&
+			len(fields[i+1].index) == len(dominant.index) &&
+			fields[i+1].tagged == dominant.tagged
+		if !ambiguous {
+			kept = append(kept, dominant)
+		}
+		i = j
+	}
+
+	// back into the order they are declared in
+	sort.Slice(kept, func(i, j int) bool {
+		a, b := kept[i].index, kept[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	s := &structFields{list: kept, byName: make(map[string]int, len(kept))}
+	for i, f := range kept {
+		s.byName[f.name] = i
+	}
+	return s
+}
@@ -0,0 +1,199 @@
+package gojson
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// FormatOptions is the style Format writes a document in
+type FormatOptions struct {
+	// Indent is repeated once for each level of nesting
+	Indent string
+
+	// Newline ends each line, "\n" when empty
+	Newline string
+
+	// SpaceAfterColon writes "key": value rather than "key":value
+	SpaceAfterColon bool
+
+	// ShortArrayWidth keeps arrays of only strings, numbers, booleans
+	// and nulls on one line, like [1, 2, 3], when that takes at most
+	// this many characters. 0 puts every element on its own line.
+	ShortArrayWidth int
+
+	// MaxLineWidth stops a short array being kept on one line when the
+	// line would be wider than this, indent included. 0 is no limit.
+	// Lines are measured in runes and tabs count as one.
+	MaxLineWidth int
+}
+
+// DefaultFormatOptions is the style gj fmt uses
+var DefaultFormatOptions = FormatOptions{
+	Indent:          "  ",
+	Newline:         "\n",
+	SpaceAfterColon: true,
+	ShortArrayWidth: 60,
+	MaxLineWidth:    100,
+}
+
+// Format returns the json document b rewritten in the style opts
+// describes, ending in a newline. Strings and numbers are kept exactly
+// as they are written.
+func Format(b []byte, opts FormatOptions) ([]byte, error) {
+	f := formatter{
+		indent:     opts.Indent,
+		newline:    opts.Newline,
+		colon:      ":",
+		arrayWidth: opts.ShortArrayWidth,
+		maxWidth:   opts.MaxLineWidth,
+	}
+	if f.newline == "" {
+		f.newline = "\n"
+	}
+	if opts.SpaceAfterColon {
+		f.colon = ": "
+	}
+
+	out, err := f.append(make([]byte, 0, len(b)+len(b)/2), b)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, f.newline...), nil
+}
+
+// appendIndent appends the json document src the way
+// encoding/json.Indent does, each member and element on a new line
+// starting with prefix and indent repeated for each level of nesting
+func appendIndent(dst, src []byte, prefix, indent string) ([]byte, error) {
+	f := formatter{prefix: prefix, indent: indent, newline: "\n", colon: ": "}
+	return f.append(dst, src)
+}
+
+// formatter re-emits a document one Lexer token at a time
+type formatter struct {
+	prefix  string // starts every line but the first
+	indent  string
+	newline string
+	colon   string // after each key
+
+	arrayWidth int // see FormatOptions
+	maxWidth   int
+}
+
+func (f *formatter) append(dst, src []byte) ([]byte, error) {
+	if err := ValidateJSON(src); err != nil {
+		return dst, err
+	}
+
+	l := NewLexer(src)
+	lineStart := len(dst) // for measuring the current line
+	depth := 0
+	open := false     // the last token opened an object or array
+	afterKey := false // the last token was a key
+	for {
+		tok, err := l.Next()
+		if err == io.EOF {
+			return dst, nil
+		}
+		if err != nil {
+			return dst, err
+		}
+
+		switch tok.Kind {
+		case TokenEndObject, TokenEndArray:
+			depth--
+			if !open {
+				dst, lineStart = f.line(dst, lineStart, depth)
+			}
+			dst = append(dst, tok.Raw...)
+			open = false
+			continue
+		}
+
+		switch {
+		case afterKey:
+			afterKey = false
+		case depth > 0:
+			if !open {
+				dst = append(dst, ',')
+			}
+			dst, lineStart = f.line(dst, lineStart, depth)
+		}
+		open = false
+
+		if tok.Kind == TokenBeginArray && f.arrayWidth > 0 {
+			var n int
+			if dst, n = f.shortArray(dst, lineStart, src[tok.Offset:]); n > 0 {
+				// the short array has been written, skip past it
+				for ; n > 1; n-- {
+					l.Next()
+				}
+				continue
+			}
+		}
+
+		dst = append(dst, tok.Raw...)
+		switch tok.Kind {
+		case TokenBeginObject, TokenBeginArray:
+			depth++
+			open = true
+		case TokenKey:
+			dst = append(dst, f.colon...)
+			afterKey = true
+		}
+	}
+}
+
+// line starts a new line at depth, returning where it starts
+func (f *formatter) line(dst []byte, lineStart, depth int) ([]byte, int) {
+	dst = append(dst, f.newline...)
+	lineStart = len(dst)
+	dst = append(dst, f.prefix...)
+	for i := 0; i < depth; i++ {
+		dst = append(dst, f.indent...)
+	}
+	return dst, lineStart
+}
+
+// shortArray appends the array src starts with on one line if it only
+// holds literals and fits. It returns how many tokens were written, 0
+// when the array doesn't qualify and dst is left as it was.
+func (f *formatter) shortArray(dst []byte, lineStart int, src []byte) ([]byte, int) {
+	start := len(dst)
+	width := 0
+
+	l := NewLexer(src)
+	for n := 1; ; n++ {
+		tok, err := l.Next()
+		if err != nil {
+			return dst[:start], 0 // can't happen, src has been validated
+		}
+
+		switch tok.Kind {
+		case TokenBeginArray:
+			if n > 1 {
+				return dst[:start], 0
+			}
+		case TokenBeginObject:
+			return dst[:start], 0
+		case TokenEndArray:
+		default:
+			if n > 2 {
+				dst = append(dst, ", "...)
+				width += 2
+			}
+		}
+		dst = append(dst, tok.Raw...)
+		width += utf8.RuneCount(tok.Raw)
+
+		if width > f.arrayWidth {
+			return dst[:start], 0
+		}
+		if tok.Kind == TokenEndArray {
+			if f.maxWidth > 0 && utf8.RuneCount(dst[lineStart:]) > f.maxWidth {
+				return dst[:start], 0
+			}
+			return dst, n
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package gojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     FormatOptions
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "literal",
+			input:    ` 1.50 `,
+			opts:     DefaultFormatOptions,
+			expected: "1.50\n",
+		},
+		{
+			name:     "empty containers",
+			input:    `{ "a" : [ ], "b": { } }`,
+			opts:     FormatOptions{Indent: "\t"},
+			expected: "{\n\t\"a\":[],\n\t\"b\":{}\n}\n",
+		},
+		{
+			name:     "space after colon and crlf",
+			input:    `{"a":{"b":null}}`,
+			opts:     FormatOptions{Indent: " ", Newline: "\r\n", SpaceAfterColon: true},
+			expected: "{\r\n \"a\": {\r\n  \"b\": null\r\n }\r\n}\r\n",
+		},
+		{
+			name:     "no short arrays",
+			input:    `[1,2]`,
+			opts:     FormatOptions{Indent: "  "},
+			expected: "[\n  1,\n  2\n]\n",
+		},
+		{
+			name:     "short arrays",
+			input:    `{"a": [1, "two", true, null], "b": [[1], {"c": 1}]}`,
+			opts:     FormatOptions{Indent: "  ", SpaceAfterColon: true, ShortArrayWidth: 30},
+			expected: "{\n  \"a\": [1, \"two\", true, null],\n  \"b\": [\n    [1],\n    {\n      \"c\": 1\n    }\n  ]\n}\n",
+		},
+		{
+			name:     "array too wide",
+			input:    `[[1, 2, 3], [1, 2, 3, 4]]`,
+			opts:     FormatOptions{Indent: "  ", ShortArrayWidth: 9},
+			expected: "[\n  [1, 2, 3],\n  [\n    1,\n    2,\n    3,\n    4\n  ]\n]\n",
+		},
+		{
+			name:     "width counts runes",
+			input:    `["日本語"]`,
+			opts:     FormatOptions{ShortArrayWidth: 7},
+			expected: "[\"日本語\"]\n",
+		},
+		{
+			name:     "line too wide",
+			input:    `{"key": [1, 2], "longer key": [1, 2]}`,
+			opts:     FormatOptions{Indent: "  ", SpaceAfterColon: true, ShortArrayWidth: 10, MaxLineWidth: 15},
+			expected: "{\n  \"key\": [1, 2],\n  \"longer key\": [\n    1,\n    2\n  ]\n}\n",
+		},
+		{
+			name:     "strings and numbers are kept",
+			input:    `["é\/", 1E+2, -0.0]`,
+			opts:     FormatOptions{ShortArrayWidth: 100},
+			expected: "[\"é\\/\", 1E+2, -0.0]\n",
+		},
+		{
+			name:    "invalid",
+			input:   `{"a": }`,
+			opts:    DefaultFormatOptions,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "trailing data",
+			input:   `{} {}`,
+			opts:    DefaultFormatOptions,
+			wantErr: ErrTrailingData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Format([]byte(tt.input), tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(actual) != tt.expected {
+				t.Errorf("expected\n%q\ngot\n%q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// TestFormatIndent checks that without short arrays Format matches
+// encoding/json.Indent
+func TestFormatIndent(t *testing.T) {
+	example := bytes.TrimSpace(readFile(t, "example.json"))
+
+	var expected bytes.Buffer
+	if err := json.Indent(&expected, example, "", "\t"); err != nil {
+		t.Fatal(err)
+	}
+	expected.WriteByte('\n')
+
+	actual, err := Format(example, FormatOptions{Indent: "\t", SpaceAfterColon: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected.Bytes()) {
+		t.Errorf("expected\n%s\ngot\n%s", expected.Bytes(), actual)
+	}
+
+	again, err := Format(actual, DefaultFormatOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	twice, err := Format(again, DefaultFormatOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, twice) {
+		t.Error("formatting a formatted document changed it")
+	}
+}
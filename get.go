@@ -0,0 +1,238 @@
+package gojson
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+var (
+	ErrNotFound    = fmt.Errorf("get: path not found")
+	ErrInvalidPath = fmt.Errorf("get: path elements must be a string or an int")
+)
+
+// Get returns the value at path in data without building anything.
+// Each element of path is a string, the key of an object member, or an
+// int, the index of an array element, e.g.
+//
+//	Get(example, 3, "name", "first")
+//
+// Values before the one wanted are scanned over and the slice returned
+// points into data. Only the parts of data scanned are checked, so
+// data may be invalid after the value found. If an object has the same
+// key more than once the first is used.
+func Get(data []byte, path ...interface{}) (Value, Kind, error) {
+	_, c := ParseWhitespace(data)
+
+	for i, p := range path {
+		var err error
+		switch p := p.(type) {
+		case string:
+			c, err = getMember(data, c, p)
+		case int:
+			c, err = getElement(data, c, p)
+		default:
+			err = ErrInvalidPath
+		}
+		if err == ErrNotFound {
+			// copied so that path doesn't escape when things are found
+			found := append([]interface{}{}, path[:i+1]...)
+			return nil, KindInvalid, fmt.Errorf("%w: %v", err, found)
+		}
+		if err != nil {
+			return nil, KindInvalid, err
+		}
+	}
+
+	v, _, err := ParseValue(data[c:])
+	if err != nil {
+		return nil, KindInvalid, shiftError(data, c, err)
+	}
+	return v, v.Kind(), nil
+}
+
+// GetString returns the string at path in data with its escapes decoded
+func GetString(data []byte, path ...interface{}) (string, error) {
+	v, _, err := Get(data, path...)
+	if err != nil {
+		return "", err
+	}
+	s, err := v.AsString()
+	if err != nil {
+		return "", err
+	}
+	return unquote(s), nil
+}
+
+// GetInt returns the integer at path in data
+func GetInt(data []byte, path ...interface{}) (int64, error) {
+	v, _, err := Get(data, path...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := v.AsNumber()
+	if err != nil {
+		return 0, err
+	}
+	return n.Int64()
+}
+
+// GetFloat returns the number at path in data
+func GetFloat(data []byte, path ...interface{}) (float64, error) {
+	v, _, err := Get(data, path...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := v.AsNumber()
+	if err != nil {
+		return 0, err
+	}
+	return n.Float64()
+}
+
+// GetBool returns the boolean at path in data
+func GetBool(data []byte, path ...interface{}) (bool, error) {
+	v, _, err := Get(data, path...)
+	if err != nil {
+		return false, err
+	}
+	b, err := v.AsBoolean()
+	if err != nil {
+		return false, err
+	}
+	return b[0] == 't', nil
+}
+
+// getMember returns where the value of the member named key starts, in
+// the object at data[c]
+func getMember(data []byte, c int, key string) (int, error) {
+	// object
+	//     '{' ws '}'
+	//     '{' members '}'
+	if c == len(data) || data[c] != '{' {
+		return 0, ErrNotFound
+	}
+	c++
+
+	_, n := ParseWhitespace(data[c:])
+	c += n
+	if c < len(data) && data[c] == '}' {
+		return 0, ErrNotFound
+	}
+
+	for {
+		// member
+		//     ws string ws ':' element
+		s, n, err := ParseString(data[c:])
+		if err != nil {
+			return 0, shiftError(data, c, err)
+		}
+		c += n
+
+		_, n = ParseWhitespace(data[c:])
+		c += n
+		if c == len(data) || data[c] != ':' {
+			return 0, syntaxError(data, c, "member", ErrInvalidMemberMissingSep)
+		}
+		c++
+
+		_, n = ParseWhitespace(data[c:])
+		c += n
+		if s.equal(key) {
+			return c, nil
+		}
+
+		if c, err = skipValue(data, c); err != nil {
+			return 0, err
+		}
+		switch {
+		case c < len(data) && data[c] == ',':
+			c++
+			_, n = ParseWhitespace(data[c:])
+			c += n
+		case c < len(data) && data[c] == '}':
+			return 0, ErrNotFound
+		default:
+			return 0, syntaxError(data, c, "object", ErrInvalidObjectClose)
+		}
+	}
+}
+
+// getElement returns where element i starts, in the array at data[c]
+func getElement(data []byte, c int, i int) (int, error) {
+	// array
+	//     '[' ws ']'
+	//     '[' elements ']'
+	if c == len(data) || data[c] != '[' || i < 0 {
+		return 0, ErrNotFound
+	}
+	c++
+
+	_, n := ParseWhitespace(data[c:])
+	c += n
+	if c < len(data) && data[c] == ']' {
+		return 0, ErrNotFound
+	}
+
+	for ; i > 0; i-- {
+		var err error
+		if c, err = skipValue(data, c); err != nil {
+			return 0, err
+		}
+		switch {
+		case c < len(data) && data[c] == ',':
+			c++
+			_, n = ParseWhitespace(data[c:])
+			c += n
+		case c < len(data) && data[c] == ']':
+			return 0, ErrNotFound
+		default:
+			return 0, syntaxError(data, c, "array", ErrInvalidArrayClose)
+		}
+	}
+	return c, nil
+}
+
+// skipValue scans over the value at data[c] and the whitespace after
+// it, returning where it stopped
+func skipValue(data []byte, c int) (int, error) {
+	_, n, err := ParseValue(data[c:])
+	if err != nil {
+		return 0, shiftError(data, c, err)
+	}
+	c += n
+
+	_, n = ParseWhitespace(data[c:])
+	return c + n, nil
+}
+
+// equal reports whether the valid string s holds key once its escapes
+// are decoded
+func (s String) equal(key string) bool {
+	b := s[1 : len(s)-1]
+	for len(b) > 0 {
+		if b[0] != '\\' {
+			// compare bytes up to the next escape as they are
+			if len(key) == 0 || b[0] != key[0] {
+				return false
+			}
+			b, key = b[1:], key[1:]
+			continue
+		}
+
+		r, n := nextRune(b)
+		b = b[n:]
+		k, size := utf8.DecodeRuneInString(key)
+		if len(key) == 0 || r != k {
+			return false
+		}
+		key = key[size:]
+	}
+	return len(key) == 0
+}
+
+// unquote returns the contents of the valid string s with its escapes
+// decoded
+func unquote(s String) string {
+	b, _ := s.Unquote()
+	return string(b)
+}
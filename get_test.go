@@ -0,0 +1,176 @@
+package gojson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	tests := []struct {
+		name     string
+		input    []byte
+		path     []interface{}
+		expected []byte
+		kind     Kind
+		wantErr  error
+	}{
+		{
+			name:     "no path is the whole document",
+			input:    []byte(` [1] `),
+			expected: []byte(`[1]`),
+			kind:     KindArray,
+		},
+		{
+			name:     "nested member",
+			input:    example,
+			path:     []interface{}{3, "name", "first"},
+			expected: []byte(`"Hayden"`),
+			kind:     KindString,
+		},
+		{
+			name:     "object",
+			input:    example,
+			path:     []interface{}{3, "friends", 1},
+			expected: []byte("{\n        \"id\": 1,\n        \"name\": \"Glenna Wyatt\"\n      }"),
+			kind:     KindObject,
+		},
+		{
+			name:     "escaped key",
+			input:    []byte(`{"aé\n": 1, "aé\\n": 2}`),
+			path:     []interface{}{"aé\\n"},
+			expected: []byte(`2`),
+			kind:     KindNumber,
+		},
+		{
+			name:     "surrogate pair key",
+			input:    []byte(`{"𝄞": null}`),
+			path:     []interface{}{"\U0001d11e"},
+			expected: []byte(`null`),
+			kind:     KindNull,
+		},
+		{
+			name:     "first of duplicate keys",
+			input:    []byte(`{"a": 1, "a": 2}`),
+			path:     []interface{}{"a"},
+			expected: []byte(`1`),
+			kind:     KindNumber,
+		},
+		{
+			name:     "invalid data after the value is not looked at",
+			input:    []byte(`[1, 2, nope`),
+			path:     []interface{}{1},
+			expected: []byte(`2`),
+			kind:     KindNumber,
+		},
+		{
+			name:    "missing key",
+			input:   example,
+			path:    []interface{}{3, "name", "middle"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "index out of range",
+			input:   example,
+			path:    []interface{}{6},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "negative index",
+			input:   example,
+			path:    []interface{}{-1},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "key of an array",
+			input:   example,
+			path:    []interface{}{"name"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "empty object",
+			input:   []byte(`{ }`),
+			path:    []interface{}{"a"},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "invalid path",
+			input:   example,
+			path:    []interface{}{3.0},
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name:    "invalid data before the value",
+			input:   []byte(`[1 2]`),
+			path:    []interface{}{1},
+			wantErr: ErrInvalidArrayClose,
+		},
+	}
+
+	for _, tc := range tests {
+		v, kind, err := Get(tc.input, tc.path...)
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: expected %v: got %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		// byte.Compare returns 0 if equal
+		if bytes.Compare(v, tc.expected) != 0 {
+			t.Errorf("%s: expected %s: got %s", tc.name, tc.expected, v)
+		}
+		if kind != tc.kind {
+			t.Errorf("%s: expected %s: got %s", tc.name, tc.kind, kind)
+		}
+	}
+}
+
+func TestGetTyped(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	s, err := GetString([]byte(`{"a": "é\t𝄞\"/"}`), "a")
+	if err != nil || s != "é\t\U0001d11e\"/" {
+		t.Errorf("GetString: got %q, %v", s, err)
+	}
+
+	i, err := GetInt(example, 3, "age")
+	if err != nil || i != 29 {
+		t.Errorf("GetInt: got %d, %v", i, err)
+	}
+
+	f, err := GetFloat([]byte(`{"a": [-1.5e3]}`), "a", 0)
+	if err != nil || f != -1500 {
+		t.Errorf("GetFloat: got %v, %v", f, err)
+	}
+
+	b, err := GetBool(example, 3, "isActive")
+	if err != nil || !b {
+		t.Errorf("GetBool: got %v, %v", b, err)
+	}
+
+	if _, err := GetString(example, 3, "age"); !errors.Is(err, ErrInvalidStringOpen) {
+		t.Errorf("GetString of a number: expected %v: got %v", ErrInvalidStringOpen, err)
+	}
+	if _, err := GetInt(example, 3, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetInt of a missing member: expected %v: got %v", ErrNotFound, err)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	example := readFile(b, "example.json")
+	b.SetBytes(int64(len(example)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := Get(example, 5, "name", "first"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -18,6 +19,8 @@ var (
 	ErrInvalidNull               = fmt.Errorf("invalid null")
 	ErrInvalidBoolean            = fmt.Errorf("invalid boolean")
 	ErrInvalidEscape             = fmt.Errorf("invalid escape")
+	ErrInvalidSurrogate          = fmt.Errorf("invalid escape: unpaired utf-16 surrogate")
+	ErrInvalidNumber             = fmt.Errorf("invalid number")
 	ErrInvalidCharacterRuneError = fmt.Errorf("invalid character: rune error")
 	ErrInvalidObjectOpen         = fmt.Errorf("invalid object: expecting '{'")
 	ErrInvalidObjectClose        = fmt.Errorf("invalid object: expecting '}'")
@@ -27,7 +30,9 @@ var (
 	ErrInvalidStringOpen         = fmt.Errorf(`invalid string: missing beginnig '"'`)
 	ErrInvalidStringClose        = fmt.Errorf(`invalid string: missing ending '"'`)
 	ErrUnexpectedChar            = fmt.Errorf("unexpected char")
+	ErrTrailingData              = fmt.Errorf("unexpected data after top-level value")
 	ErrParseInteger              = fmt.Errorf("parse error: not an integer")
+	ErrNumberRange               = fmt.Errorf("parse error: number out of range")
 
 	ErrUnsupported = fmt.Errorf("unsupported: should we panic") // todo
 
@@ -36,25 +41,42 @@ var (
 	NullValue  = []byte(`null`)
 )
 
+// ParseJSON consumes a single json document from the front of b and
+// returns it along with the number of bytes consumed. Anything after
+// the document is left for the caller, which is what you want when
+// reading a stream of documents. Use ValidateJSON when all of b must be
+// a single document.
+//
+// ParseJSON enforces DefaultParseOptions.
 func ParseJSON(b []byte) ([]byte, int, error) {
-	// TODO add unit test for this...
-
-	// json
-	//     element
-
-	_, c, err := ParseElement(b)
-	if err != nil {
-		return nil, 0, err
-	}
+	return DefaultParseOptions.ParseJSON(b)
+}
 
-	return b[:c], c, nil
+// ValidateJSON returns nil if the whole of b is exactly one json
+// document. Trailing bytes that are not whitespace are reported as
+// ErrTrailingData positioned at the first of them.
+//
+// ValidateJSON enforces DefaultParseOptions.
+func ValidateJSON(b []byte) error {
+	return DefaultParseOptions.ValidateJSON(b)
+}
 
+// Valid reports whether the whole of b is exactly one json document
+func Valid(b []byte) bool {
+	return ValidateJSON(b) == nil
 }
 
+// Value is any json value, see Value.Kind for which
 type Value []byte
 
-// type value needs to indicate object array string number true, false, or null
+// ParseValue consumes the value at the front of b. It does not skip
+// leading whitespace.
 func ParseValue(b []byte) (Value, int, error) {
+	p := parser{ParseOptions: DefaultParseOptions}
+	return p.value(b)
+}
+
+func (p *parser) value(b []byte) (Value, int, error) {
 	// value
 	//     object
 	//     array
@@ -64,49 +86,62 @@ func ParseValue(b []byte) (Value, int, error) {
 	//     "false"
 	//     "null"
 
-	// TODO add unit test for this...
-
 	if len(b) == 0 {
-		return nil, 0, ErrEOF
-	}
-
-	// TODO is this preformant? we attempt each type and rescan on
-	// failures, see if we can guess which Parse func to call
-
-	// TODO order of Parse func matters, e.g. I assume null is not
-	// used frequently so we call it last
-
-	_, c, err := ParseObject(b)
-	if err == nil {
-		return b[:c], c, nil
+		return nil, 0, syntaxError(b, 0, "value", ErrEOF)
 	}
 
-	_, c, err = ParseArray(b)
-	if err == nil {
-		return b[:c], c, nil
+	// the first byte tells us which value we are looking at, so each
+	// value is scanned exactly once and whatever error the chosen
+	// Parse func returns is the real error
+	var c int
+	var err error
+	switch b[0] {
+	case '{':
+		_, c, err = p.object(b)
+	case '[':
+		_, c, err = p.array(b)
+	case '"':
+		_, c, err = p.str(b)
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		_, c, err = ParseNumber(b)
+		if err == nil {
+			err = checkNumberEnd(b, c)
+		}
+	case 't', 'f':
+		_, c, err = ParseBoolean(b)
+	case 'n':
+		_, c, err = ParseNull(b)
+	default:
+		err = syntaxError(b, 0, "value", ErrUnsupported)
 	}
-
-	_, c, err = ParseString(b)
-	if err == nil {
-		return b[:c], c, nil
+	if err != nil {
+		return nil, 0, err
 	}
 
-	_, c, err = ParseNumber(b)
-	if err == nil {
-		return b[:c], c, nil
-	}
+	return b[:c], c, nil
+}
 
-	_, c, err = ParseBoolean(b)
-	if err == nil {
-		return b[:c], c, nil
+// checkNumberEnd reports whether the number b[:c] is followed by
+// something that can only belong to it. ParseNumber consumes the
+// longest valid prefix so "1." and "1e+" stop early, but as values
+// they are malformed. The error points at the byte that broke
+// the number, which is the end of b if b ends in "1." or "1e+".
+func checkNumberEnd(b []byte, c int) error {
+	if c == len(b) {
+		return nil
 	}
 
-	_, c, err = ParseNull(b)
-	if err == nil {
-		return b[:c], c, nil
+	switch b[c] {
+	case '.':
+		// no digits after the '.'
+		return syntaxError(b, c+1, "number", ErrInvalidNumber)
+	case 'e', 'E':
+		// no digits after the 'e' and its sign
+		_, n := ParseSign(b[c+1:])
+		return syntaxError(b, c+1+n, "number", ErrInvalidNumber)
 	}
 
-	return nil, 0, ErrUnsupported
+	return nil
 }
 
 // if a value is null it doesn't give us any information about the type e.g. it could be an array or an object
@@ -124,7 +159,17 @@ func ParseNull(b []byte) (Null, int, error) {
 		// "null"
 		return b[:len(NullValue)], len(NullValue), nil
 	}
-	return nil, 0, ErrInvalidNull
+	return nil, 0, syntaxError(b, literalMismatch(b, NullValue), "null", ErrInvalidNull)
+}
+
+// literalMismatch returns the offset of the first byte of b that
+// doesn't match lit. When b is a truncated lit this is len(b).
+func literalMismatch(b, lit []byte) int {
+	var i int
+	for i < len(b) && i < len(lit) && b[i] == lit[i] {
+		i++
+	}
+	return i
 }
 
 type Boolean []byte
@@ -144,20 +189,35 @@ func ParseBoolean(b []byte) (Boolean, int, error) {
 		return b[:len(FalseValue)], len(FalseValue), nil
 	}
 
-	return nil, 0, ErrInvalidBoolean
+	lit := TrueValue
+	if len(b) > 0 && b[0] == FalseValue[0] {
+		lit = FalseValue
+	}
+	return nil, 0, syntaxError(b, literalMismatch(b, lit), "boolean", ErrInvalidBoolean)
 }
 
-type Object Value // todo map[string]Element
+// Object is an object as it appears in the input, see ParseNode for its
+// members
+type Object Value
 
 func ParseObject(b []byte) (Object, int, error) {
+	p := parser{ParseOptions: DefaultParseOptions}
+	return p.object(b)
+}
+
+func (p *parser) object(b []byte) (Object, int, error) {
 	// object
 	//     '{' ws '}'
 	//     '{' members '}'
 
 	// copy/pasta from ParseArray
 	if len(b) == 0 || b[0] != '{' {
-		return nil, 0, ErrInvalidObjectOpen
+		return nil, 0, syntaxError(b, 0, "object", ErrInvalidObjectOpen)
 	}
+	if err := p.push(b); err != nil {
+		return nil, 0, err
+	}
+	defer p.pop()
 	c := 1 // consume the '{'
 
 	// attempt to consume whitspace a check for closing '}'
@@ -170,9 +230,9 @@ func ParseObject(b []byte) (Object, int, error) {
 	}
 	c -= consumed // unconsume the whitespace and let it be part of elements
 
-	_, consumed, err := ParseMembers(b[c:])
+	consumed, trailing, err := p.members(b[c:])
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, shiftError(b, c, err)
 	}
 	c += consumed
 
@@ -182,7 +242,12 @@ func ParseObject(b []byte) (Object, int, error) {
 		return b[:c], c, nil
 	}
 
-	return nil, 0, ErrInvalidObjectClose
+	if trailing != nil {
+		// the member after the last ',' is what actually went wrong
+		return nil, 0, shiftError(b, c, trailing)
+	}
+
+	return nil, 0, syntaxError(b, c, "object", ErrInvalidObjectClose)
 }
 
 // type members   ... this should be map[string] Value
@@ -191,36 +256,57 @@ func ParseMembers(b []byte) ([]byte, int, error) {
 	//     member
 	//     member ',' members
 
-	_, consumed, err := ParseMember(b)
+	p := parser{ParseOptions: DefaultParseOptions}
+	c, _, err := p.members(b)
 	if err != nil {
-		// must parse at least one member
 		return nil, 0, err
 	}
-	c := consumed
+	return b[:c], c, nil
+}
+
+// members is ParseMembers but also returns trailing, the error from the
+// member following an unconsumed ',' (positioned relative to that ','),
+// so ParseObject can report it instead of a missing '}'. trailing isn't
+// a failure of members itself, err is.
+func (p *parser) members(b []byte) (c int, trailing error, err error) {
+	_, consumed, err := p.member(b)
+	if err != nil {
+		// must parse at least one member
+		return 0, nil, err
+	}
+	c = consumed
 
 	//  while there's moreToConsume && the expected delimeter ',' is there...
-	for len(b[c:]) > 0 && b[c:][0] == ',' {
+	for n := 1; len(b[c:]) > 0 && b[c:][0] == ','; n++ {
+		if p.MaxMembers > 0 && n == p.MaxMembers {
+			return 0, nil, syntaxError(b, c, "members", ErrMaxMembersExceeded)
+		}
 		c++ // consume the ','
-		_, consumed, err = ParseMember(b[c:])
+		_, consumed, err = p.member(b[c:])
 		if err != nil {
 			c-- // unconsume the last ','
-			break
+			return c, shiftError(b[c:], 1, err), nil
 		}
 		c += consumed
 	}
-	return b[:c], c, nil
+	return c, nil, nil
 }
 
 func ParseMember(b []byte) ([]byte, int, error) {
+	p := parser{ParseOptions: DefaultParseOptions}
+	return p.member(b)
+}
+
+func (p *parser) member(b []byte) ([]byte, int, error) {
 	// member
 	//     ws string ws ':' element
 
 	_, consumed := ParseWhitespace(b)
 	c := consumed
 
-	_, consumed, err := ParseString(b[c:])
+	_, consumed, err := p.str(b[c:])
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, shiftError(b, c, err)
 	}
 	c += consumed
 
@@ -229,13 +315,13 @@ func ParseMember(b []byte) ([]byte, int, error) {
 
 	// noMoreToConsume || next unconsumed byte is not ':'  TODO make this if stmt a func... if !nextCharIs(b[c:], ':')
 	if len(b[c:]) == 0 || b[c:][0] != ':' {
-		return nil, 0, ErrInvalidMemberMissingSep
+		return nil, 0, syntaxError(b, c, "member", ErrInvalidMemberMissingSep)
 	}
 	c += 1 // consume the ':'
 
-	_, consumed, err = ParseElement(b[c:])
+	_, consumed, err = p.element(b[c:])
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, shiftError(b, c, err)
 	}
 	c += consumed
 
@@ -243,13 +329,22 @@ func ParseMember(b []byte) ([]byte, int, error) {
 }
 
 func ParseArray(b []byte) ([]byte, int, error) {
+	p := parser{ParseOptions: DefaultParseOptions}
+	return p.array(b)
+}
+
+func (p *parser) array(b []byte) ([]byte, int, error) {
 	// array
 	//     '[' ws ']'
 	//     '[' elements ']'
 
 	if len(b) == 0 || b[0] != '[' {
-		return nil, 0, ErrInvalidArrayOpen
+		return nil, 0, syntaxError(b, 0, "array", ErrInvalidArrayOpen)
+	}
+	if err := p.push(b); err != nil {
+		return nil, 0, err
 	}
+	defer p.pop()
 	c := 1 // consume the '['
 
 	// attempt to consume whitspace a check for closing ']'
@@ -262,9 +357,9 @@ func ParseArray(b []byte) ([]byte, int, error) {
 	}
 	c -= consumed // unconsume whitespace and let it be part of elements
 
-	_, consumed, err := ParseElements(b[c:])
+	consumed, trailing, err := p.elements(b[c:])
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, shiftError(b, c, err)
 	}
 	c += consumed
 
@@ -274,47 +369,75 @@ func ParseArray(b []byte) ([]byte, int, error) {
 		return b[:c], c, nil
 	}
 
-	return nil, 0, ErrInvalidArrayClose
+	if trailing != nil {
+		// the element after the last ',' is what actually went wrong
+		return nil, 0, shiftError(b, c, trailing)
+	}
+
+	return nil, 0, syntaxError(b, c, "array", ErrInvalidArrayClose)
 }
 
-type Elements []byte // todo this should eventually be []Value
+// Elements are the elements of an array as they appear in the input,
+// see ParseNode for each of them
+type Elements []byte
 
 func ParseElements(b []byte) (Elements, int, error) {
 	// elements
 	//     element
 	//     element ',' elements
 
-	_, consumed, err := ParseElement(b)
+	p := parser{ParseOptions: DefaultParseOptions}
+	c, _, err := p.elements(b)
 	if err != nil {
-		// must parse at least one element
 		return nil, 0, err
 	}
-	c := consumed
+	return b[:c], c, nil
+}
+
+// elements is ParseElements but also returns trailing, the error from
+// the element following an unconsumed ',' (positioned relative to that
+// ','), so ParseArray can report it instead of a missing ']'. trailing
+// isn't a failure of elements itself, err is.
+func (p *parser) elements(b []byte) (c int, trailing error, err error) {
+	_, consumed, err := p.element(b)
+	if err != nil {
+		// must parse at least one element
+		return 0, nil, err
+	}
+	c = consumed
 
 	//  while there's moreToConsume && the expected delimeter ',' is there...
-	for len(b[c:]) > 0 && b[c:][0] == ',' {
+	for n := 1; len(b[c:]) > 0 && b[c:][0] == ','; n++ {
+		if p.MaxMembers > 0 && n == p.MaxMembers {
+			return 0, nil, syntaxError(b, c, "elements", ErrMaxMembersExceeded)
+		}
 		c++ // consume the ','
-		_, consumed, err = ParseElement(b[c:])
+		_, consumed, err = p.element(b[c:])
 		if err != nil {
 			c-- // unconsume the last ','
-			break
+			return c, shiftError(b[c:], 1, err), nil
 		}
 		c += consumed
 	}
-	return b[:c], c, nil
+	return c, nil, nil
 }
 
 type Element []byte
 
 func ParseElement(b []byte) (Element, int, error) {
+	p := parser{ParseOptions: DefaultParseOptions}
+	return p.element(b)
+}
+
+func (p *parser) element(b []byte) (Element, int, error) {
 	// element
 	//     ws value ws
 
 	_, c := ParseWhitespace(b)
 
-	_, consumed, err := ParseValue(b[c:])
+	_, consumed, err := p.value(b[c:])
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, shiftError(b, c, err)
 	}
 	c += consumed
 
@@ -326,7 +449,8 @@ func ParseElement(b []byte) (Element, int, error) {
 
 type String []byte
 
-// String returns the string without the surrounding quotes
+// String returns the string without the surrounding quotes. Escapes are
+// left as they are, see Unquote to decode them.
 func (s String) String() string {
 	l := len(s)
 	if l < 2 {
@@ -336,17 +460,105 @@ func (s String) String() string {
 	return string(s[1 : l-1]) // this could get expensive...
 }
 
+// Unquote returns the contents of s with every escape decoded to utf-8.
+// If there are no escapes it returns the contents of s as they are,
+// without allocating.
+//
+// Unquote expects s to come from ParseString, only its escapes are
+// checked.
+func (s String) Unquote() ([]byte, error) {
+	if err := s.checkQuotes(); err != nil {
+		return nil, err
+	}
+
+	b := s[1 : len(s)-1]
+	if bytes.IndexByte(b, '\\') == -1 {
+		return b, nil
+	}
+
+	out, err := s.AppendUnquoted(make([]byte, 0, len(b)))
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AppendUnquoted appends the contents of s, with every escape decoded
+// to utf-8, to dst and returns the extended slice. Like Unquote it only
+// checks the escapes.
+func (s String) AppendUnquoted(dst []byte) ([]byte, error) {
+	if err := s.checkQuotes(); err != nil {
+		return dst, err
+	}
+
+	b := s[1 : len(s)-1]
+	c := 1 // where b is in s, for errors
+	for {
+		i := bytes.IndexByte(b, '\\')
+		if i == -1 {
+			return append(dst, b...), nil
+		}
+		dst = append(dst, b[:i]...)
+		b, c = b[i:], c+i
+
+		// ParseCharacter checks the escape, including both halves of
+		// a surrogate pair
+		_, n, err := ParseCharacter(b)
+		if err != nil {
+			return dst, shiftError(s, c, err)
+		}
+		var buf [utf8.UTFMax]byte
+		r, _ := nextRune(b)
+		dst = append(dst, buf[:utf8.EncodeRune(buf[:], r)]...)
+		b, c = b[n:], c+n
+	}
+}
+
+func (s String) checkQuotes() error {
+	if len(s) == 0 || s[0] != '"' {
+		return syntaxError(s, 0, "string", ErrInvalidStringOpen)
+	}
+	if len(s) == 1 || s[len(s)-1] != '"' {
+		return syntaxError(s, len(s), "string", ErrInvalidStringClose)
+	}
+	return nil
+}
+
+// nextRune decodes the character at the front of b, which holds the
+// contents of a valid string. A surrogate pair is decoded as one
+// character. It returns the character and how many bytes it took up.
+func nextRune(b []byte) (rune, int) {
+	if b[0] != '\\' {
+		return utf8.DecodeRune(b)
+	}
+
+	if b[1] != 'u' {
+		return Escape(b[1:2]).Rune(), 2
+	}
+	r := Escape(b[1:6]).Rune()
+	if !utf16.IsSurrogate(r) {
+		return r, 6
+	}
+	// the string is valid so the low half follows
+	return utf16.DecodeRune(r, Escape(b[7:12]).Rune()), 12
+}
+
 func ParseString(b []byte) (String, int, error) {
+	p := parser{ParseOptions: DefaultParseOptions}
+	return p.str(b)
+}
+
+func (p *parser) str(b []byte) (String, int, error) {
 	// string
 	//     '"' characters '"'
 
 	if len(b) == 0 {
 		// need at least two double quotes
-		return nil, 0, ErrEOF
+		return nil, 0, syntaxError(b, 0, "string", ErrEOF)
 	}
 
 	if b[0] != '"' {
-		return nil, 0, ErrInvalidStringOpen
+		return nil, 0, syntaxError(b, 0, "string", ErrInvalidStringOpen)
 	}
 
 	c := 1 // we've consumed the first double quote
@@ -354,9 +566,19 @@ func ParseString(b []byte) (String, int, error) {
 	_, consumed := ParseCharacters(b[c:])
 	c += consumed
 
+	if p.MaxStringLength > 0 && consumed > p.MaxStringLength {
+		return nil, 0, syntaxError(b, 1+p.MaxStringLength, "string", ErrMaxStringLengthExceeded)
+	}
+
 	// noMoreToConsume next unconsumed byte is not '"'
-	if len(b[c:]) == 0 || b[c:][0] != '"' {
-		return nil, 0, ErrInvalidStringClose
+	if len(b[c:]) == 0 {
+		return nil, 0, syntaxError(b, c, "string", ErrInvalidStringClose)
+	}
+	if b[c:][0] != '"' {
+		// ParseCharacters stopped on an invalid character, rescan
+		// it to find out why
+		_, _, err := ParseCharacter(b[c:])
+		return nil, 0, shiftError(b, c, err)
 	}
 
 	c += 1 // consume final quote
@@ -364,25 +586,9 @@ func ParseString(b []byte) (String, int, error) {
 	return b[:c], c, nil
 }
 
+// Number is a json number, see number.go for its conversions
 type Number []byte
 
-func (n Number) Int() (int64, error) {
-	d := bytes.IndexByte(n, '.')
-	if d == -1 {
-		return strconv.ParseInt(string(n), 10, 64)
-	}
-	return 0, ErrParseInteger // TODO is this even being used?
-}
-
-func (n Number) Float64() float64 {
-	ret, err := strconv.ParseFloat(string(n), 64)
-	if err != nil {
-		// then we did not parse digits correctly
-		panic(err) // todo how do return message that makes sense, when we ParsedDigits there was an error that you ignored
-	}
-	return ret
-}
-
 func ParseNumber(b []byte) (Number, int, error) {
 	// number
 	//     int frac exp
@@ -426,10 +632,14 @@ func ParseInt(b []byte) (Int, int, error) {
 	// note: int cannot have a '+' sign
 
 	if len(b) == 0 {
-		return nil, 0, ErrEOF
+		return nil, 0, syntaxError(b, 0, "int", ErrEOF)
 	}
 
 	if b[0] == '0' {
+		if len(b) > 1 && IsDigit(b[1]) {
+			// a leading zero, "01" is not "0" followed by "1"
+			return nil, 0, syntaxError(b, 1, "int", ErrInvalidNumber)
+		}
 		return b[:1], 1, nil // digit
 	}
 
@@ -444,16 +654,16 @@ func ParseInt(b []byte) (Int, int, error) {
 	}
 
 	if b[0] != '-' {
-		return nil, 0, ErrUnexpectedChar
+		return nil, 0, syntaxError(b, 0, "int", ErrUnexpectedChar)
 	}
 
 	if len(b) > 1 && b[1] == '-' {
-		return nil, 0, ErrUnexpectedChar
+		return nil, 0, syntaxError(b, 1, "int", ErrUnexpectedChar)
 	}
 
 	_, c, err := ParseInt(b[1:])
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, shiftError(b, 1, err)
 	}
 	c += 1 // the negative
 
@@ -572,16 +782,11 @@ func ParseCharacters(b []byte) ([]byte, int) {
 	//     ""
 	//     character characters
 
-	if len(b) == 0 {
-		return nil, 0
-	}
-
-	_, c, err := ParseCharacter(b)
-	if err != nil {
-		return nil, 0
-	}
+	var c int
 
-	for err == nil {
+	// '"' is what ends every string, so check for it here rather
+	// than letting ParseCharacter build an error for it every time
+	for c < len(b) && b[c] != '"' {
 		_, consumed, err := ParseCharacter(b[c:])
 		if err != nil {
 			break
@@ -603,20 +808,47 @@ func ParseCharacter(b []byte) ([]byte, int, error) {
 	// most time is spent inside this function so we should avoid mallocs
 
 	if len(b) == 0 {
-		return nil, 0, ErrEOF
+		return nil, 0, syntaxError(b, 0, "character", ErrEOF)
 	}
 
 	if b[0] == '\\' { // single backslash character
-		_, consumed, err := ParseEscape(b[1:])
+		e, consumed, err := ParseEscape(b[1:])
 		if err != nil {
-			return nil, 0, ErrInvalidCharacter
+			return nil, 0, shiftError(b, 1, err)
 		}
 		consumed += 1 // we consumed the backslash
+
+		// characters outside the BMP are escaped as a utf-16 surrogate
+		// pair e.g. "\ud834\udd1e", the halves are only valid together
+		r := e.Rune()
+		if utf16.IsSurrogate(r) {
+			if r >= 0xdc00 {
+				return nil, 0, syntaxError(b, 0, "escape", ErrInvalidSurrogate)
+			}
+			// b may simply end before the low half, in which case the
+			// error points at the end of b
+			rest := b[consumed:]
+			if len(rest) == 0 || (len(rest) == 1 && rest[0] == '\\') {
+				return nil, 0, syntaxError(b, len(b), "escape", ErrInvalidSurrogate)
+			}
+			if rest[0] != '\\' {
+				return nil, 0, syntaxError(b, 0, "escape", ErrInvalidSurrogate)
+			}
+			low, n, err := ParseEscape(rest[1:])
+			if err != nil {
+				return nil, 0, shiftError(b, consumed+1, err)
+			}
+			if low.Rune() < 0xdc00 || low.Rune() > 0xdfff {
+				return nil, 0, syntaxError(b, 0, "escape", ErrInvalidSurrogate)
+			}
+			consumed += 1 + n
+		}
+
 		return b[:consumed], consumed, nil
 	}
 
 	if b[0] == '"' {
-		return nil, 0, ErrInvalidCharacter
+		return nil, 0, syntaxError(b, 0, "character", ErrInvalidCharacter)
 	}
 
 	// 0x10ffff overflows the length of a byte
@@ -627,52 +859,92 @@ func ParseCharacter(b []byte) ([]byte, int, error) {
 	// r contains the first rune of the string
 	// size is the size of the rune in bytes
 
-	if r == utf8.RuneError {
-		return nil, 0, ErrInvalidCharacterRuneError
+	// a size of 1 means b isn't valid utf-8, otherwise b really does
+	// hold U+FFFD which is fine
+	if r == utf8.RuneError && size == 1 {
+		if !utf8.FullRune(b) {
+			// b ends part way through a multi-byte character
+			return nil, 0, syntaxError(b, len(b), "character", ErrInvalidCharacterRuneError)
+		}
+		return nil, 0, syntaxError(b, 0, "character", ErrInvalidCharacterRuneError)
 	}
 
 	if 0x0020 <= r && r <= 0x10ffff {
 		return b[:size], size, nil
 	}
 
-	return nil, 0, ErrInvalidCharacter
+	return nil, 0, syntaxError(b, 0, "character", ErrInvalidCharacter)
 }
 
 type Escape []byte
 
+// Rune returns the character the escape stands for. For a '\u' escape
+// this is a single utf-16 code unit, which may be half of a surrogate
+// pair. Rune returns utf8.RuneError if e is not a valid escape.
+func (e Escape) Rune() rune {
+	if len(e) == 0 {
+		return utf8.RuneError
+	}
+
+	switch e[0] {
+	case '"', '\\', '/':
+		return rune(e[0])
+	case 'b':
+		return '\b'
+	case 'f':
+		return '\f'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'u':
+		if len(e) != 5 {
+			break
+		}
+		var r rune
+		for _, h := range e[1:] {
+			r = r<<4 | hexValue(h)
+		}
+		return r
+	}
+
+	return utf8.RuneError
+}
+
 func ParseEscape(b []byte) (Escape, int, error) {
 	// escape
 	//     '"'
 	//     '\'
 	//     '/'
 	//     'b'
+	//     'f'
 	//     'n'
 	//     'r'
 	//     't'
 	//     'u' hex hex hex hex
 
 	if len(b) == 0 {
-		return nil, 0, ErrEOF
+		return nil, 0, syntaxError(b, 0, "escape", ErrEOF)
 	}
 
-SWITCH:
 	switch b[0] {
 	// '\\' is single backslash character
-	case '"', '\\', '/', 'b', 'n', 'r', 't':
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
 		return b[:1], 1, nil
 	case 'u':
-		if len(b) < 5 {
-			break // not enough hex to consume
-		}
 		for i := 1; i < 5; i++ {
-			if !IsHex(b[i]) {
-				break SWITCH
+			if i == len(b) || !IsHex(b[i]) {
+				// point at the bad hex, or the end of b if there
+				// wasn't enough hex to consume
+				return nil, 0, syntaxError(b, i, "escape", ErrInvalidEscape)
 			}
 		}
 		return b[:5], 5, nil
 	}
 
-	return nil, 0, ErrInvalidEscape
+	return nil, 0, syntaxError(b, 0, "escape", ErrInvalidEscape)
 }
 
 type Whitespace []byte
@@ -728,12 +1000,12 @@ func ParseDigits(b []byte) (Digits, int, error) {
 	//     digit digits
 
 	if len(b) == 0 {
-		return nil, 0, ErrEOF
+		return nil, 0, syntaxError(b, 0, "digits", ErrEOF)
 	}
 
 	// check first digit
 	if !IsDigit(b[0]) {
-		return nil, 0, ErrInvalidDigit
+		return nil, 0, syntaxError(b, 0, "digits", ErrInvalidDigit)
 	}
 
 	// consume as many digits as possible
@@ -759,3 +1031,14 @@ func IsHex(b byte) bool {
 		('a' <= b && b <= 'f')
 
 }
+
+// hexValue returns the value of the hex digit b, b must satisfy IsHex
+func hexValue(b byte) rune {
+	switch {
+	case IsDigit(b):
+		return rune(b - '0')
+	case 'A' <= b && b <= 'F':
+		return rune(b-'A') + 10
+	}
+	return rune(b-'a') + 10
+}
@@ -1,6 +1,7 @@
 package gojson
 
 import (
+	"os"
 	"testing"
 )
 
@@ -301,9 +302,9 @@ func TestParseInt(t *testing.T) {
 			expected: []byte("2432323"),
 		},
 		{
-			name:     "can only consume one zero",
-			input:    []byte("000002432323"),
-			expected: []byte("0"),
+			name:    "leading zero",
+			input:   []byte("000002432323"),
+			wantErr: true,
 		},
 		{
 			name:     "negative zero is ok",
@@ -317,9 +318,9 @@ func TestParseInt(t *testing.T) {
 			expected: []byte("-123456"),
 		},
 		{
-			name:     "can only consume one negative zero",
-			input:    []byte("-000002432323"),
-			expected: []byte("-0"),
+			name:    "negative leading zero",
+			input:   []byte("-000002432323"),
+			wantErr: true,
 		},
 		{
 			name:    "cant have multiple negative signs",
@@ -379,9 +380,9 @@ func TestParseNumber(t *testing.T) {
 			expected: []byte("2432323"),
 		},
 		{
-			name:     "can only consume one zero",
-			input:    []byte("000002432323"),
-			expected: []byte("0"),
+			name:    "leading zero",
+			input:   []byte("000002432323"),
+			wantErr: true,
 		},
 		{
 			name:     "negative zero is ok",
@@ -395,9 +396,9 @@ func TestParseNumber(t *testing.T) {
 			expected: []byte("-123456"),
 		},
 		{
-			name:     "can only consume one negative zero",
-			input:    []byte("-000002432323"),
-			expected: []byte("-0"),
+			name:    "negative leading zero",
+			input:   []byte("-000002432323"),
+			wantErr: true,
 		},
 		{
 			name:    "cant have multiple negative signs",
@@ -507,6 +508,11 @@ func TestParseEscape(t *testing.T) {
 			input:    []byte(`r whatabout linefeed? is that just \n`),
 			expected: []byte(`r`),
 		},
+		{
+			name:     "f for form feed",
+			input:    []byte(`f is for form feed`),
+			expected: []byte(`f`),
+		},
 		{
 			name:     "t is for tab",
 			input:    []byte(`talso is whitespace`),
@@ -585,8 +591,55 @@ func TestParseCharacter(t *testing.T) {
 			expected: []byte(string([]rune{0x10ffff})),
 		},
 		{
+			// string([]rune{0x110000}) would give us U+FFFD, so spell
+			// out the bytes 0x110000 would be if utf-8 allowed it
 			name:    "greater than 0x10ffff",
-			input:   []byte(string([]rune{0x110000})),
+			input:   []byte{0xf4, 0x90, 0x80, 0x80},
+			wantErr: true,
+		},
+		{
+			name:     "U+FFFD is okay",
+			input:    []byte("\ufffd rest"),
+			expected: []byte("\ufffd"),
+		},
+		{
+			name:    "invalid utf-8",
+			input:   []byte{0xc0, 0xaf},
+			wantErr: true,
+		},
+		{
+			name:     "form feed escape",
+			input:    []byte(`\f asdfa`),
+			expected: []byte(`\f`),
+		},
+		{
+			name:     "surrogate pair",
+			input:    []byte(`\ud834\udd1e asdfa`),
+			expected: []byte(`\ud834\udd1e`),
+		},
+		{
+			name:    "lone high surrogate",
+			input:   []byte(`\ud800 asdfa`),
+			wantErr: true,
+		},
+		{
+			name:    "lone high surrogate at end",
+			input:   []byte(`\ud800`),
+			wantErr: true,
+		},
+		{
+			name:    "high surrogate followed by another escape",
+			input:   []byte(`\ud800\n`),
+			wantErr: true,
+		},
+		{
+			name:    "lone low surrogate",
+			input:   []byte(`\udc00`),
+			wantErr: true,
+		},
+		{
+			name:    "inverted surrogates",
+			input:   []byte(`\udd1e\ud834`),
 			wantErr: true,
 		},
 		{
@@ -663,7 +716,7 @@ func TestParseCharacters(t *testing.T) {
 		},
 		{
 			name:     "stop at anything greater than 0x10ffff",
-			input:    []byte(string([]rune{0x40, 0x34, 0x110000})),
+			input:    []byte{0x40, 0x34, 0xf4, 0x90, 0x80, 0x80},
 			expected: []byte(string([]rune{0x40, 0x34})),
 		},
 		{
@@ -798,6 +851,312 @@ func TestParseString(t *testing.T) {
 	}
 }
 
+func TestStringUnquote(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "no escapes",
+			input:    []byte(`"héllo"`),
+			expected: []byte("héllo"),
+		},
+		{
+			name:     "empty",
+			input:    []byte(`""`),
+			expected: []byte(""),
+		},
+		{
+			name:     "every short escape",
+			input:    []byte(`"\"\\\/\b\f\n\r\t"`),
+			expected: []byte("\"\\/\b\f\n\r\t"),
+		},
+		{
+			name:     "unicode escapes",
+			input:    []byte(`"\u00e9\u20AC\u0000x"`),
+			expected: []byte("é€\x00x"),
+		},
+		{
+			name:     "surrogate pair",
+			input:    []byte(`"a\ud834\udd1eb"`),
+			expected: []byte("a\U0001d11eb"),
+		},
+		{
+			name:     "escape next to raw utf-8",
+			input:    []byte(`"é\né"`),
+			expected: []byte("é\né"),
+		},
+		{
+			name:    "invalid escape",
+			input:   []byte(`"a\x"`),
+			wantErr: true,
+		},
+		{
+			name:    "unpaired surrogate",
+			input:   []byte(`"\ud834x"`),
+			wantErr: true,
+		},
+		{
+			name:    "missing quotes",
+			input:   []byte(`abc`),
+			wantErr: true,
+		},
+		{
+			name:    "just a quote",
+			input:   []byte(`"`),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			actual, err := String(tc.input).Unquote()
+
+			if tc.wantErr && err == nil {
+				t.Errorf("expecting error but got <nil>")
+			} else if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+
+			// byte.Compare
+			if string(tc.expected) != string(actual) {
+				t.Errorf("unexpected return: wanted %q got %q", string(tc.expected), string(actual))
+			}
+
+			appended, err := String(tc.input).AppendUnquoted([]byte("prefix"))
+			if !tc.wantErr && (err != nil || string(appended) != "prefix"+string(tc.expected)) {
+				t.Errorf("unexpected AppendUnquoted: got %q, %v", string(appended), err)
+			}
+		})
+	}
+}
+
+func TestStringUnquoteDoesNotAllocate(t *testing.T) {
+	plain := String(`"no escapes in here"`)
+	escaped := String(`"tab\there \u00e9 \ud834\udd1e"`)
+	dst := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := plain.Unquote(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := escaped.AppendUnquoted(dst[:0]); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations: got %v", allocs)
+	}
+
+	// the contents of s itself when there's nothing to decode
+	b, _ := plain.Unquote()
+	if &b[0] != &plain[1] {
+		t.Errorf("expected Unquote to return a slice of s")
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "whitespace is consumed on both sides",
+			input:    []byte(" \n {\"a\": [1, 2]} \n"),
+			expected: []byte(" \n {\"a\": [1, 2]} \n"),
+		},
+		{
+			name:     "stop at trailing garbage",
+			input:    []byte(`{"a":1} garbage`),
+			expected: []byte(`{"a":1} `),
+		},
+		{
+			name:     "stream of documents",
+			input:    []byte(`[1] [2] [3]`),
+			expected: []byte(`[1] `),
+		},
+		{
+			name:    "all whitespace",
+			input:   []byte("  \t\n "),
+			wantErr: true,
+		},
+		{
+			name:    "nil input",
+			input:   nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			actual, actualLen, err := ParseJSON(tc.input)
+
+			if tc.wantErr && err == nil {
+				t.Errorf("expecting error but got <nil>")
+			} else if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+
+			if len(tc.expected) != actualLen {
+				t.Errorf("unexpected length: wanted %d got %d", len(tc.expected), actualLen)
+			}
+
+			// byte.Compare
+			if string(tc.expected) != string(actual) {
+				t.Errorf("unexpected return: wanted %q got %q", string(tc.expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	tests := []testCase{
+		{
+			name:  "whitespace around document",
+			input: []byte(" \n {\"a\": [1, 2]} \n"),
+		},
+		{
+			name:  "example.json",
+			input: readFile(t, "example.json"),
+		},
+		{
+			name:    "trailing garbage",
+			input:   []byte(`{"a":1} garbage`),
+			wantErr: true,
+		},
+		{
+			name:    "two documents",
+			input:   []byte(`[1] [2]`),
+			wantErr: true,
+		},
+		{
+			name:    "badExample.json",
+			input:   readFile(t, "badExample.json"),
+			wantErr: true,
+		},
+		{
+			name:    "nil input",
+			input:   nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			err := ValidateJSON(tc.input)
+
+			if tc.wantErr && err == nil {
+				t.Errorf("expecting error but got <nil>")
+			} else if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+
+			if Valid(tc.input) != !tc.wantErr {
+				t.Errorf("Valid disagrees with ValidateJSON")
+			}
+		})
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []testCase{
+		{
+			name:     "object",
+			input:    []byte(`{"a": [1, 2]}, "rest"`),
+			expected: []byte(`{"a": [1, 2]}`),
+		},
+		{
+			name:     "array",
+			input:    []byte(`[ {}, [], "" ]]`),
+			expected: []byte(`[ {}, [], "" ]`),
+		},
+		{
+			name:     "string",
+			input:    []byte(`"jim" "bob"`),
+			expected: []byte(`"jim"`),
+		},
+		{
+			name:     "negative number",
+			input:    []byte(`-12.5e3,`),
+			expected: []byte(`-12.5e3`),
+		},
+		{
+			name:     "true",
+			input:    []byte(`true]`),
+			expected: []byte(`true`),
+		},
+		{
+			name:     "false",
+			input:    []byte(`false}`),
+			expected: []byte(`false`),
+		},
+		{
+			name:     "null",
+			input:    []byte(`null `),
+			expected: []byte(`null`),
+		},
+		{
+			name:    "no leading spaces",
+			input:   []byte(`  null`),
+			wantErr: true,
+		},
+		{
+			name:    "not a value",
+			input:   []byte(`undefined`),
+			wantErr: true,
+		},
+		{
+			name:    "leading zero",
+			input:   []byte(`01`),
+			wantErr: true,
+		},
+		{
+			name:    "frac with no digits",
+			input:   []byte(`1.]`),
+			wantErr: true,
+		},
+		{
+			name:    "exp with no digits",
+			input:   []byte(`1e+]`),
+			wantErr: true,
+		},
+		{
+			name:    "bad nested value",
+			input:   []byte(`[1, [2, tru]]`),
+			wantErr: true,
+		},
+		{
+			name:    "nil input",
+			input:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "emtpy slice",
+			input:   []byte{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			actual, actualLen, err := ParseValue(tc.input)
+
+			if tc.wantErr && err == nil {
+				t.Errorf("expecting error but got <nil>")
+			} else if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+
+			if len(tc.expected) != actualLen {
+				t.Errorf("unexpected length: wanted %d got %d", len(tc.expected), actualLen)
+			}
+
+			// byte.Compare
+			if string(tc.expected) != string(actual) {
+				t.Errorf("unexpected return: wanted %q got %q", string(tc.expected), string(actual))
+			}
+		})
+	}
+}
+
 func TestParseElement(t *testing.T) {
 	tests := []testCase{
 		{
@@ -1375,3 +1734,50 @@ func TestParseNull(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkParseJSON(b *testing.B) {
+	example, err := os.ReadFile("example.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(example)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _, err := ParseJSON(example)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseValue(b *testing.B) {
+	values := [][]byte{
+		[]byte(`{"a": [1, 2, 3], "b": {"c": null}}`),
+		[]byte(`[true, false, null, "str", -12.5e3]`),
+		[]byte(`"a string with an \n escape"`),
+		[]byte(`-1234.5678e+90`),
+		[]byte(`true`),
+		[]byte(`false`),
+		[]byte(`null`),
+	}
+
+	var size int64
+	for _, v := range values {
+		size += int64(len(v))
+	}
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, v := range values {
+			_, _, err := ParseValue(v)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gojson
+
+import (
+	"io"
+)
+
+// Handler receives the structure of a document from ParseWithHandler
+// as it is scanned. Every slice points into the input, nothing is
+// copied. offset is where the thing being reported starts in the input.
+//
+// A non-nil error from any method stops ParseWithHandler, which
+// returns that error as is.
+type Handler interface {
+	OnObjectStart(offset int) error
+	OnObjectEnd(offset int) error
+	OnArrayStart(offset int) error
+	OnArrayEnd(offset int) error
+
+	// OnKey is called with the quoted key of each member before the
+	// member's value is reported
+	OnKey(key String, offset int) error
+
+	// OnValue is called for every string, number, true, false and null.
+	// kind is one of TokenString, TokenNumber, TokenTrue, TokenFalse or
+	// TokenNull.
+	OnValue(v Value, kind TokenKind, offset int) error
+}
+
+// BaseHandler does nothing with every callback. Embed it to implement
+// only the Handler methods you need.
+type BaseHandler struct{}
+
+func (BaseHandler) OnObjectStart(int) error             { return nil }
+func (BaseHandler) OnObjectEnd(int) error               { return nil }
+func (BaseHandler) OnArrayStart(int) error              { return nil }
+func (BaseHandler) OnArrayEnd(int) error                { return nil }
+func (BaseHandler) OnKey(String, int) error             { return nil }
+func (BaseHandler) OnValue(Value, TokenKind, int) error { return nil }
+
+// ParseWithHandler scans one json document from the front of b, the
+// same as ParseJSON, calling h as it goes. It returns the number of
+// bytes consumed.
+//
+// ParseWithHandler enforces DefaultParseOptions.
+func ParseWithHandler(b []byte, h Handler) (int, error) {
+	return DefaultParseOptions.ParseWithHandler(b, h)
+}
+
+// parseWithHandler runs l over one document, reporting it to h
+func parseWithHandler(l *Lexer, h Handler) (int, error) {
+	// json
+	//     element
+
+	for {
+		tok, err := l.Next()
+		if err == io.EOF {
+			return 0, syntaxError(l.b, l.c, "value", ErrEOF)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		switch tok.Kind {
+		case TokenBeginObject:
+			err = h.OnObjectStart(tok.Offset)
+		case TokenEndObject:
+			err = h.OnObjectEnd(tok.Offset)
+		case TokenBeginArray:
+			err = h.OnArrayStart(tok.Offset)
+		case TokenEndArray:
+			err = h.OnArrayEnd(tok.Offset)
+		case TokenKey:
+			err = h.OnKey(String(tok.Raw), tok.Offset)
+		default:
+			err = h.OnValue(Value(tok.Raw), tok.Kind, tok.Offset)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if len(l.stack) == 0 {
+			// the document is complete
+			break
+		}
+	}
+
+	l.skipWhitespace()
+	return l.c, nil
+}
@@ -0,0 +1,128 @@
+package gojson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recorder writes every callback it gets to a string
+type recorder struct {
+	strings.Builder
+}
+
+func (r *recorder) OnObjectStart(offset int) error {
+	fmt.Fprintf(r, "{@%d ", offset)
+	return nil
+}
+
+func (r *recorder) OnObjectEnd(offset int) error {
+	fmt.Fprintf(r, "}@%d ", offset)
+	return nil
+}
+
+func (r *recorder) OnArrayStart(offset int) error {
+	fmt.Fprintf(r, "[@%d ", offset)
+	return nil
+}
+
+func (r *recorder) OnArrayEnd(offset int) error {
+	fmt.Fprintf(r, "]@%d ", offset)
+	return nil
+}
+
+func (r *recorder) OnKey(key String, offset int) error {
+	fmt.Fprintf(r, "%s:@%d ", string(key), offset)
+	return nil
+}
+
+func (r *recorder) OnValue(v Value, kind TokenKind, offset int) error {
+	fmt.Fprintf(r, "%s(%s)@%d ", kind, v, offset)
+	return nil
+}
+
+func TestParseWithHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		consumed int
+		wantErr  bool
+	}{
+		{
+			name:     "scalar",
+			input:    ` "jim"  rest`,
+			expected: `String("jim")@1 `,
+			consumed: 8,
+		},
+		{
+			name:     "nested",
+			input:    `{"a": [1, null], "b": {}} [2]`,
+			expected: `{@0 "a":@1 [@6 Number(1)@7 Null(null)@10 ]@14 "b":@17 {@22 }@23 }@24 `,
+			consumed: 26,
+		},
+		{
+			name:     "callbacks stop at the error",
+			input:    `[true, false, nope]`,
+			expected: `[@0 True(true)@1 False(false)@7 `,
+			wantErr:  true,
+		},
+		{
+			name:    "empty input",
+			input:   `  `,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var r recorder
+
+			consumed, err := ParseWithHandler([]byte(tc.input), &r)
+
+			if tc.wantErr && err == nil {
+				t.Errorf("expecting error but got <nil>")
+			} else if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+
+			if tc.consumed != consumed {
+				t.Errorf("unexpected length: wanted %d got %d", tc.consumed, consumed)
+			}
+			if tc.expected != r.String() {
+				t.Errorf("unexpected callbacks: wanted %q got %q", tc.expected, r.String())
+			}
+		})
+	}
+}
+
+// emailCounter only cares about keys
+type emailCounter struct {
+	BaseHandler
+	n int
+}
+
+var errStop = errors.New("stop")
+
+func (c *emailCounter) OnKey(key String, _ int) error {
+	if key.String() == "email" {
+		c.n++
+		if c.n == 3 {
+			return errStop
+		}
+	}
+	return nil
+}
+
+func TestParseWithHandlerStop(t *testing.T) {
+	c := &emailCounter{}
+
+	_, err := ParseWithHandler(readFile(t, "example.json"), c)
+	if err != errStop {
+		t.Errorf("expecting the handler's error but got %v", err)
+	}
+	if c.n != 3 {
+		t.Errorf("unexpected count: %d", c.n)
+	}
+}
@@ -0,0 +1,859 @@
+package gojson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var ErrInvalidJSONPath = fmt.Errorf("jsonpath: invalid query")
+
+// JSONPath is a compiled JSONPath query (RFC 9535). It supports the
+// root identifier, child and descendant segments, name, wildcard,
+// index, slice and filter selectors, and filter expressions made of
+// existence tests and comparisons joined with &&, || and !. Function
+// extensions such as length() are not supported.
+type JSONPath struct {
+	segments []segment
+}
+
+// PathMatch is a node selected by a JSONPath and the Pointer to it
+type PathMatch struct {
+	Pointer Pointer
+	Node    *Node
+}
+
+type segment struct {
+	descendant bool // ".." rather than "."
+	selectors  []selector
+}
+
+type selectorKind int
+
+const (
+	selectName selectorKind = iota
+	selectWildcard
+	selectIndex
+	selectSlice
+	selectFilter
+)
+
+type selector struct {
+	kind selectorKind
+	name string
+
+	// index, or the slice start:end:step
+	index, end, step int
+	hasIndex, hasEnd bool
+
+	filter filter
+}
+
+// ParseJSONPath compiles a JSONPath query such as
+// "$.store.book[?@.price < 10].title". A malformed query is reported
+// as a *SyntaxError wrapping ErrInvalidJSONPath, positioned in query.
+func ParseJSONPath(query string) (*JSONPath, error) {
+	// jsonpath-query
+	//     root-identifier segments
+	p := pathParser{s: query}
+	if p.peek() != '$' {
+		return nil, p.fail("'$'")
+	}
+	p.c++
+
+	segments, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	if p.c != len(p.s) {
+		return nil, p.fail("segment")
+	}
+	return &JSONPath{segments: segments}, nil
+}
+
+// QueryJSONPath runs query over the json document data
+func QueryJSONPath(data []byte, query string) ([]PathMatch, error) {
+	path, err := ParseJSONPath(query)
+	if err != nil {
+		return nil, err
+	}
+
+	n, c, err := ParseNode(data)
+	if err != nil {
+		return nil, err
+	}
+	if c != len(data) {
+		return nil, syntaxError(data, c, "end of input", ErrTrailingData)
+	}
+
+	return path.Query(&n), nil
+}
+
+// Query returns the nodes of the tree root that p selects, in the
+// order RFC 9535 gives them
+func (p *JSONPath) Query(root *Node) []PathMatch {
+	return p.query(root, PathMatch{Pointer: Pointer{}, Node: root})
+}
+
+// query runs p from start, which is root for a query beginning with '$'
+// or the current node for one beginning with '@'
+func (p *JSONPath) query(root *Node, start PathMatch) []PathMatch {
+	nodes := []PathMatch{start}
+	for _, seg := range p.segments {
+		var next []PathMatch
+		for _, m := range nodes {
+			if !seg.descendant {
+				next = seg.apply(root, m, next)
+				continue
+			}
+			descend(m, func(d PathMatch) {
+				next = seg.apply(root, d, next)
+			})
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descend calls f with m and then each of its descendants, in the
+// order they appear in the document
+func descend(m PathMatch, f func(PathMatch)) {
+	f(m)
+	for i := range m.Node.Members {
+		descend(m.member(i), f)
+	}
+	for i := range m.Node.Elements {
+		descend(m.element(i), f)
+	}
+}
+
+// member returns the value of m's i'th member
+func (m PathMatch) member(i int) PathMatch {
+	return m.child(unquote(m.Node.Members[i].Key), &m.Node.Members[i].Value)
+}
+
+// element returns m's i'th element
+func (m PathMatch) element(i int) PathMatch {
+	return m.child(strconv.Itoa(i), &m.Node.Elements[i])
+}
+
+func (m PathMatch) child(tok string, n *Node) PathMatch {
+	p := make(Pointer, len(m.Pointer)+1)
+	copy(p, m.Pointer)
+	p[len(m.Pointer)] = tok
+	return PathMatch{Pointer: p, Node: n}
+}
+
+// apply appends what each of seg's selectors selects from m to out
+func (seg segment) apply(root *Node, m PathMatch, out []PathMatch) []PathMatch {
+	n := m.Node
+	for _, s := range seg.selectors {
+		switch s.kind {
+		case selectName:
+			for i := range n.Members {
+				if n.Members[i].Key.equal(s.name) {
+					out = append(out, m.member(i))
+					break
+				}
+			}
+		case selectWildcard:
+			for i := range n.Members {
+				out = append(out, m.member(i))
+			}
+			for i := range n.Elements {
+				out = append(out, m.element(i))
+			}
+		case selectIndex:
+			i := s.index
+			if i < 0 {
+				i += len(n.Elements)
+			}
+			if i >= 0 && i < len(n.Elements) {
+				out = append(out, m.element(i))
+			}
+		case selectSlice:
+			if n.Kind == KindArray {
+				out = s.slice(m, out)
+			}
+		case selectFilter:
+			for i := range n.Members {
+				if c := m.member(i); s.filter.test(root, c) {
+					out = append(out, c)
+				}
+			}
+			for i := range n.Elements {
+				if c := m.element(i); s.filter.test(root, c) {
+					out = append(out, c)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// slice appends the elements of the array m selected by the slice s,
+// following section 2.3.4.2.2 of RFC 9535
+func (s selector) slice(m PathMatch, out []PathMatch) []PathMatch {
+	n := len(m.Node.Elements)
+	if s.step == 0 {
+		return out
+	}
+
+	start, end := 0, n
+	if s.step < 0 {
+		start, end = n-1, -n-1
+	}
+	if s.hasIndex {
+		start = s.index
+	}
+	if s.hasEnd {
+		end = s.end
+	}
+	if start < 0 {
+		start += n
+	}
+	if end < 0 {
+		end += n
+	}
+
+	if s.step > 0 {
+		lower, upper := clamp(start, 0, n), clamp(end, 0, n)
+		for i := lower; i < upper; i += s.step {
+			out = append(out, m.element(i))
+		}
+		return out
+	}
+
+	upper, lower := clamp(start, -1, n-1), clamp(end, -1, n-1)
+	for i := upper; lower < i; i += s.step {
+		out = append(out, m.element(i))
+	}
+	return out
+}
+
+func clamp(i, lower, upper int) int {
+	if i < lower {
+		return lower
+	}
+	if i > upper {
+		return upper
+	}
+	return i
+}
+
+// filter is a logical expression in a filter selector
+type filter interface {
+	test(root *Node, current PathMatch) bool
+}
+
+type orFilter []filter
+
+func (f orFilter) test(root *Node, current PathMatch) bool {
+	for _, g := range f {
+		if g.test(root, current) {
+			return true
+		}
+	}
+	return false
+}
+
+type andFilter []filter
+
+func (f andFilter) test(root *Node, current PathMatch) bool {
+	for _, g := range f {
+		if !g.test(root, current) {
+			return false
+		}
+	}
+	return true
+}
+
+type notFilter struct{ f filter }
+
+func (f notFilter) test(root *Node, current PathMatch) bool {
+	return !f.f.test(root, current)
+}
+
+// existsFilter is true when its query selects anything
+type existsFilter struct{ q operand }
+
+func (f existsFilter) test(root *Node, current PathMatch) bool {
+	return len(f.q.query.query(root, f.q.start(root, current))) > 0
+}
+
+type compareFilter struct {
+	op          string
+	left, right operand
+}
+
+func (f compareFilter) test(root *Node, current PathMatch) bool {
+	a, b := f.left.value(root, current), f.right.value(root, current)
+	switch f.op {
+	case "==":
+		return equalNodes(a, b)
+	case "!=":
+		return !equalNodes(a, b)
+	case "<":
+		return lessNodes(a, b)
+	case "<=":
+		return lessNodes(a, b) || equalNodes(a, b)
+	case ">":
+		return lessNodes(b, a)
+	case ">=":
+		return lessNodes(b, a) || equalNodes(a, b)
+	}
+	return false
+}
+
+// operand is a literal or a query in a filter expression
+type operand struct {
+	literal  *Node
+	query    *JSONPath
+	relative bool // the query starts at '@'
+}
+
+func (o operand) start(root *Node, current PathMatch) PathMatch {
+	if o.relative {
+		return current
+	}
+	return PathMatch{Pointer: Pointer{}, Node: root}
+}
+
+// value returns the node o refers to, or nil if a query selects
+// nothing
+func (o operand) value(root *Node, current PathMatch) *Node {
+	if o.literal != nil {
+		return o.literal
+	}
+	nodes := o.query.query(root, o.start(root, current))
+	if len(nodes) != 1 {
+		return nil
+	}
+	return nodes[0].Node
+}
+
+// singular reports whether q selects at most one node, as the queries
+// compared in a filter must
+func (q *JSONPath) singular() bool {
+	for _, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 ||
+			(seg.selectors[0].kind != selectName && seg.selectors[0].kind != selectIndex) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalNodes compares a and b as section 2.3.5.2.2 of RFC 9535 does,
+// where nil is an empty query result
+func equalNodes(a, b *Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	switch a.Kind {
+	case KindNumber:
+		return numberValue(a.Raw) == numberValue(b.Raw)
+	case KindString:
+		return unquote(String(a.Raw)) == unquote(String(b.Raw))
+	case KindArray:
+		if len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !equalNodes(&a.Elements[i], &b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case KindObject:
+		if len(a.Members) != len(b.Members) {
+			return false
+		}
+		for i := range a.Members {
+			var found bool
+			for j := range b.Members {
+				if unquote(a.Members[i].Key) == unquote(b.Members[j].Key) {
+					found = equalNodes(&a.Members[i].Value, &b.Members[j].Value)
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	// true, false and null
+	return string(a.Raw) == string(b.Raw)
+}
+
+// lessNodes orders numbers and strings, anything else is unordered
+func lessNodes(a, b *Node) bool {
+	if a == nil || b == nil || a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case KindNumber:
+		return numberValue(a.Raw) < numberValue(b.Raw)
+	case KindString:
+		// comparing utf-8 bytes orders by unicode scalar value
+		return unquote(String(a.Raw)) < unquote(String(b.Raw))
+	}
+	return false
+}
+
+func numberValue(v Value) float64 {
+	// out of range numbers come back as ±Inf, which still order
+	f, _ := strconv.ParseFloat(string(v), 64)
+	return f
+}
+
+// pathParser parses a JSONPath query, c is how much of s has been
+// consumed
+type pathParser struct {
+	s string
+	c int
+}
+
+func (p *pathParser) fail(expected string) error {
+	return syntaxError([]byte(p.s), p.c, expected, ErrInvalidJSONPath)
+}
+
+// peek returns the next byte, or 0 at the end of the query
+func (p *pathParser) peek() byte {
+	if p.c == len(p.s) {
+		return 0
+	}
+	return p.s[p.c]
+}
+
+func (p *pathParser) skipBlank() {
+	// B
+	//     %x20 / %x09 / %x0A / %x0D
+	for p.c < len(p.s) {
+		switch p.s[p.c] {
+		case ' ', '\t', '\n', '\r':
+			p.c++
+		default:
+			return
+		}
+	}
+}
+
+func (p *pathParser) segments() ([]segment, error) {
+	// segments
+	//     *(S segment)
+	var segments []segment
+	for {
+		c := p.c
+		p.skipBlank()
+		if p.peek() != '.' && p.peek() != '[' {
+			// the blank space belongs to whatever follows the query
+			p.c = c
+			return segments, nil
+		}
+
+		seg, err := p.segment()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+}
+
+func (p *pathParser) segment() (segment, error) {
+	// child-segment
+	//     bracketed-selection
+	//     "." (wildcard-selector / member-name-shorthand)
+	// descendant-segment
+	//     ".." (bracketed-selection / wildcard-selector / member-name-shorthand)
+	var seg segment
+	if p.peek() == '[' {
+		return p.bracketed(seg)
+	}
+
+	p.c++ // consume the '.'
+	if p.peek() == '.' {
+		seg.descendant = true
+		p.c++
+		if p.peek() == '[' {
+			return p.bracketed(seg)
+		}
+	}
+
+	if p.peek() == '*' {
+		p.c++
+		seg.selectors = []selector{{kind: selectWildcard}}
+		return seg, nil
+	}
+
+	name := p.shorthand()
+	if name == "" {
+		return seg, p.fail("member name")
+	}
+	seg.selectors = []selector{{kind: selectName, name: name}}
+	return seg, nil
+}
+
+// shorthand consumes a member-name-shorthand
+func (p *pathParser) shorthand() string {
+	// name-first
+	//     ALPHA / "_" / %x80-10FFFF
+	// name-char
+	//     name-first / DIGIT
+	start := p.c
+	for p.c < len(p.s) {
+		b := p.s[p.c]
+		if b == '_' || b >= 0x80 || (b|0x20 >= 'a' && b|0x20 <= 'z') ||
+			(p.c > start && IsDigit(b)) {
+			p.c++
+			continue
+		}
+		break
+	}
+	return p.s[start:p.c]
+}
+
+func (p *pathParser) bracketed(seg segment) (segment, error) {
+	// bracketed-selection
+	//     "[" S selector *(S "," S selector) S "]"
+	p.c++ // consume the '['
+	for {
+		p.skipBlank()
+		s, err := p.selector()
+		if err != nil {
+			return seg, err
+		}
+		seg.selectors = append(seg.selectors, s)
+
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.c++
+		case ']':
+			p.c++
+			return seg, nil
+		default:
+			return seg, p.fail("',' or ']'")
+		}
+	}
+}
+
+func (p *pathParser) selector() (selector, error) {
+	// selector
+	//     name-selector
+	//     wildcard-selector
+	//     slice-selector
+	//     index-selector
+	//     filter-selector
+	switch b := p.peek(); {
+	case b == '\'' || b == '"':
+		s, err := p.stringLiteral()
+		if err != nil {
+			return selector{}, err
+		}
+		return selector{kind: selectName, name: unquote(s)}, nil
+	case b == '*':
+		p.c++
+		return selector{kind: selectWildcard}, nil
+	case b == '?':
+		p.c++
+		p.skipBlank()
+		f, err := p.or()
+		if err != nil {
+			return selector{}, err
+		}
+		return selector{kind: selectFilter, filter: f}, nil
+	case b == '-' || b == ':' || IsDigit(b):
+		return p.indexOrSlice()
+	}
+	return selector{}, p.fail("selector")
+}
+
+func (p *pathParser) indexOrSlice() (selector, error) {
+	// slice-selector
+	//     [start S] ":" S [end S] [":" [S step]]
+	// index-selector
+	//     int
+	s := selector{kind: selectIndex, step: 1}
+
+	var err error
+	if p.peek() != ':' {
+		if s.index, err = p.integer(); err != nil {
+			return s, err
+		}
+		s.hasIndex = true
+
+		c := p.c
+		p.skipBlank()
+		if p.peek() != ':' {
+			p.c = c
+			return s, nil
+		}
+	}
+
+	s.kind = selectSlice
+	p.c++ // consume the ':'
+	p.skipBlank()
+	if b := p.peek(); b == '-' || IsDigit(b) {
+		if s.end, err = p.integer(); err != nil {
+			return s, err
+		}
+		s.hasEnd = true
+		p.skipBlank()
+	}
+
+	if p.peek() == ':' {
+		p.c++
+		p.skipBlank()
+		if b := p.peek(); b == '-' || IsDigit(b) {
+			if s.step, err = p.integer(); err != nil {
+				return s, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// integer consumes an int, which has no leading zeros and must be
+// exactly representable in an ieee 754 double
+func (p *pathParser) integer() (int, error) {
+	// int
+	//     "0" / (["-"] DIGIT1 *DIGIT)
+	start := p.c
+	if p.peek() == '-' {
+		p.c++
+	}
+	digits := p.c
+	for p.c < len(p.s) && IsDigit(p.s[p.c]) {
+		p.c++
+	}
+
+	d := p.s[digits:p.c]
+	if d == "" || (d[0] == '0' && (len(d) > 1 || digits > start)) {
+		p.c = start
+		return 0, p.fail("integer")
+	}
+	i, err := strconv.ParseInt(p.s[start:p.c], 10, 64)
+	if err != nil || i > 1<<53-1 || i < -(1<<53-1) {
+		p.c = start
+		return 0, p.fail("integer")
+	}
+	return int(i), nil
+}
+
+// stringLiteral consumes a single or double quoted string literal and
+// returns it as a json string
+func (p *pathParser) stringLiteral() (String, error) {
+	start := p.c
+	q := p.s[p.c]
+	p.c++
+
+	// single quotes may hold '"' and escape '\'', swap these round to
+	// make a json string
+	js := []byte{'"'}
+	for ; p.c < len(p.s) && p.s[p.c] != q; p.c++ {
+		b := p.s[p.c]
+		switch {
+		case b == '\\' && p.c+1 < len(p.s) && p.s[p.c+1] == '\'' && q == '\'':
+			p.c++
+			js = append(js, '\'')
+		case b == '\\' && p.c+1 < len(p.s):
+			p.c++
+			js = append(js, b, p.s[p.c])
+		case b == '"':
+			js = append(js, '\\', '"')
+		default:
+			js = append(js, b)
+		}
+	}
+	if p.c == len(p.s) {
+		return nil, p.fail("string")
+	}
+	p.c++ // consume the closing quote
+	js = append(js, '"')
+
+	s, c, err := ParseString(js)
+	if err != nil || c != len(js) {
+		p.c = start
+		return nil, p.fail("string")
+	}
+	return s, nil
+}
+
+func (p *pathParser) or() (filter, error) {
+	// logical-or-expr
+	//     logical-and-expr *(S "||" S logical-and-expr)
+	var or orFilter
+	for {
+		f, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, f)
+
+		p.skipBlank()
+		if !p.consume("||") {
+			break
+		}
+		p.skipBlank()
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *pathParser) and() (filter, error) {
+	// logical-and-expr
+	//     basic-expr *(S "&&" S basic-expr)
+	var and andFilter
+	for {
+		f, err := p.basic()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, f)
+
+		p.skipBlank()
+		if !p.consume("&&") {
+			break
+		}
+		p.skipBlank()
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *pathParser) basic() (filter, error) {
+	// basic-expr
+	//     paren-expr
+	//     comparison-expr
+	//     test-expr
+	if p.consume("!") {
+		p.skipBlank()
+		f, err := p.basic()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := f.(compareFilter); ok {
+			// only a paren-expr or test-expr can be negated
+			return nil, p.fail("'(' or query")
+		}
+		return notFilter{f}, nil
+	}
+
+	if p.consume("(") {
+		p.skipBlank()
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		p.skipBlank()
+		if !p.consume(")") {
+			return nil, p.fail("')'")
+		}
+		return f, nil
+	}
+
+	start := p.c
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	c := p.c
+	p.skipBlank()
+	op := p.comparison()
+	if op == "" {
+		p.c = c
+		if left.query == nil {
+			return nil, p.fail("comparison")
+		}
+		return existsFilter{left}, nil
+	}
+	if left.query != nil && !left.query.singular() {
+		p.c = start
+		return nil, p.fail("singular query")
+	}
+
+	p.skipBlank()
+	start = p.c
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if right.query != nil && !right.query.singular() {
+		p.c = start
+		return nil, p.fail("singular query")
+	}
+	return compareFilter{op: op, left: left, right: right}, nil
+}
+
+// comparison consumes a comparison-op
+func (p *pathParser) comparison() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *pathParser) operand() (operand, error) {
+	// comparable
+	//     literal
+	//     singular-query
+	switch b := p.peek(); {
+	case b == '@' || b == '$':
+		p.c++
+		segments, err := p.segments()
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{query: &JSONPath{segments: segments}, relative: b == '@'}, nil
+	case b == '\'' || b == '"':
+		s, err := p.stringLiteral()
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{literal: &Node{Kind: KindString, Raw: Value(s)}}, nil
+	case b == '-' || IsDigit(b):
+		rest := []byte(p.s[p.c:])
+		n, c, err := ParseNumber(rest)
+		if err == nil {
+			err = checkNumberEnd(rest, c)
+		}
+		if err != nil {
+			return operand{}, p.fail("number")
+		}
+		p.c += c
+		return operand{literal: &Node{Kind: KindNumber, Raw: Value(n)}}, nil
+	}
+
+	for _, lit := range []string{"true", "false", "null"} {
+		if p.consume(lit) {
+			kind := KindBool
+			if lit == "null" {
+				kind = KindNull
+			}
+			return operand{literal: &Node{Kind: kind, Raw: Value(lit)}}, nil
+		}
+	}
+	return operand{}, p.fail("filter expression")
+}
+
+// consume consumes tok if it is next
+func (p *pathParser) consume(tok string) bool {
+	if len(p.s)-p.c >= len(tok) && p.s[p.c:p.c+len(tok)] == tok {
+		p.c += len(tok)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,269 @@
+package gojson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// bookstore is the example document from section 1.5 of RFC 9535
+var bookstore = []byte(`{ "store": {
+    "book": [
+      { "category": "reference",
+        "author": "Nigel Rees",
+        "title": "Sayings of the Century",
+        "price": 8.95
+      },
+      { "category": "fiction",
+        "author": "Evelyn Waugh",
+        "title": "Sword of Honour",
+        "price": 12.99
+      },
+      { "category": "fiction",
+        "author": "Herman Melville",
+        "title": "Moby Dick",
+        "isbn": "0-553-21311-3",
+        "price": 8.99
+      },
+      { "category": "fiction",
+        "author": "J. R. R. Tolkien",
+        "title": "The Lord of the Rings",
+        "isbn": "0-395-19395-8",
+        "price": 22.99
+      }
+    ],
+    "bicycle": {
+      "color": "red",
+      "price": 399
+    }
+  }
+}`)
+
+func TestJSONPath(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	tests := []struct {
+		name  string
+		input []byte
+		query string
+
+		// expected is the Pointer and Raw of each match, one per line
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "root",
+			input:    []byte(` [1] `),
+			query:    `$`,
+			expected: ` [1]`,
+		},
+		{
+			name:  "authors",
+			input: bookstore,
+			query: `$.store.book[*].author`,
+			expected: `/store/book/0/author "Nigel Rees"
+/store/book/1/author "Evelyn Waugh"
+/store/book/2/author "Herman Melville"
+/store/book/3/author "J. R. R. Tolkien"`,
+		},
+		{
+			name:  "all authors",
+			input: bookstore,
+			query: `$..author`,
+			expected: `/store/book/0/author "Nigel Rees"
+/store/book/1/author "Evelyn Waugh"
+/store/book/2/author "Herman Melville"
+/store/book/3/author "J. R. R. Tolkien"`,
+		},
+		{
+			name:     "wildcard member",
+			input:    bookstore,
+			query:    `$.store.*.color`,
+			expected: `/store/bicycle/color "red"`,
+		},
+		{
+			name:  "all prices",
+			input: bookstore,
+			query: `$.store..price`,
+			expected: `/store/book/0/price 8.95
+/store/book/1/price 12.99
+/store/book/2/price 8.99
+/store/book/3/price 22.99
+/store/bicycle/price 399`,
+		},
+		{
+			name:     "index",
+			input:    bookstore,
+			query:    `$..book[2].title`,
+			expected: `/store/book/2/title "Moby Dick"`,
+		},
+		{
+			name:     "negative index",
+			input:    bookstore,
+			query:    `$..book[-1]['title']`,
+			expected: `/store/book/3/title "The Lord of the Rings"`,
+		},
+		{
+			name:  "union",
+			input: bookstore,
+			query: `$.store.book[0, 3, 0].price`,
+			expected: `/store/book/0/price 8.95
+/store/book/3/price 22.99
+/store/book/0/price 8.95`,
+		},
+		{
+			name:  "slice",
+			input: bookstore,
+			query: `$.store.book[:2].price`,
+			expected: `/store/book/0/price 8.95
+/store/book/1/price 12.99`,
+		},
+		{
+			name:  "slice with a negative step",
+			input: []byte(`[0, 1, 2, 3, 4, 5]`),
+			query: `$[5:1:-2]`,
+			expected: `/5 5
+/3 3`,
+		},
+		{
+			name:  "reversed",
+			input: []byte(`[0, 1, 2]`),
+			query: `$[::-1]`,
+			expected: `/2 2
+/1 1
+/0 0`,
+		},
+		{
+			name:     "zero step",
+			input:    []byte(`[0, 1, 2]`),
+			query:    `$[::0]`,
+			expected: ``,
+		},
+		{
+			name:  "existence filter",
+			input: bookstore,
+			query: `$..book[?@.isbn].title`,
+			expected: `/store/book/2/title "Moby Dick"
+/store/book/3/title "The Lord of the Rings"`,
+		},
+		{
+			name:  "comparison filter",
+			input: bookstore,
+			query: `$..book[?@.price<10].title`,
+			expected: `/store/book/0/title "Sayings of the Century"
+/store/book/2/title "Moby Dick"`,
+		},
+		{
+			name:  "logical filter",
+			input: bookstore,
+			query: `$..book[?(@.category == 'fiction' && !@.isbn) || @.price >= 22.99].author`,
+			expected: `/store/book/1/author "Evelyn Waugh"
+/store/book/3/author "J. R. R. Tolkien"`,
+		},
+		{
+			name:     "comparing with the root",
+			input:    []byte(`{"max": 2, "a": [1, 2, 3]}`),
+			query:    `$.a[?@ < $.max]`,
+			expected: `/a/0 1`,
+		},
+		{
+			name:  "numbers compare by value",
+			input: []byte(`[1, 1.0, 10e-1, "1", true]`),
+			query: `$[?@ == 1]`,
+			expected: `/0 1
+/1 1.0
+/2 10e-1`,
+		},
+		{
+			name:     "missing is only equal to missing",
+			input:    []byte(`[{"a": null}, {}]`),
+			query:    `$[?@.a == $.nope]`,
+			expected: `/1 {}`,
+		},
+		{
+			name:     "escaped names",
+			input:    []byte(`{"a/b\"c": {"é": 1}}`),
+			query:    `$["a/b\"c"]['é']`,
+			expected: `/a~1b"c/é 1`,
+		},
+		{
+			name:  "older than 30",
+			input: example,
+			query: `$[?@.age > 30].email`,
+			expected: `/2/email "jerry.hensley@qaboos.co.uk"
+/4/email "tamera.wiley@apexia.tv"
+/5/email "whitehead.robertson@acium.me"`,
+		},
+		{
+			name:    "missing root",
+			input:   bookstore,
+			query:   `.store`,
+			wantErr: ErrInvalidJSONPath,
+		},
+		{
+			name:    "unclosed bracket",
+			input:   bookstore,
+			query:   `$.store[0`,
+			wantErr: ErrInvalidJSONPath,
+		},
+		{
+			name:    "leading zero",
+			input:   bookstore,
+			query:   `$.store.book[01]`,
+			wantErr: ErrInvalidJSONPath,
+		},
+		{
+			name:    "comparing a query that isn't singular",
+			input:   bookstore,
+			query:   `$.store.book[?@.* == 1]`,
+			wantErr: ErrInvalidJSONPath,
+		},
+		{
+			name:    "functions are not supported",
+			input:   bookstore,
+			query:   `$.store.book[?length(@.title) > 10]`,
+			wantErr: ErrInvalidJSONPath,
+		},
+		{
+			name:    "invalid document",
+			input:   []byte(`[1] 2`),
+			query:   `$`,
+			wantErr: ErrTrailingData,
+		},
+	}
+
+	for _, tc := range tests {
+		matches, err := QueryJSONPath(tc.input, tc.query)
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: expected %v: got %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		var lines []string
+		for _, m := range matches {
+			lines = append(lines, m.Pointer.String()+" "+string(m.Node.Raw))
+		}
+		if actual := strings.Join(lines, "\n"); actual != tc.expected {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestJSONPathErrorPosition(t *testing.T) {
+	_, err := ParseJSONPath(`$.a[?@.b = 1]`)
+
+	var se *SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected a *SyntaxError: got %v", err)
+	}
+	// "@.b" is taken as an existence test, so the '=' is unexpected
+	if se.Offset != 9 || se.Byte != '=' {
+		t.Errorf("expected '=' at offset 9: got %q at %d", se.Byte, se.Offset)
+	}
+}
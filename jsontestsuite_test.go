@@ -0,0 +1,98 @@
+package gojson
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// implementationDefined is whether ParseJSON should accept each of the
+// i_ files from the JSONTestSuite
+var implementationDefined = map[string]bool{
+	// numbers are only checked against the grammar, converting them is
+	// up to the caller
+	"i_number_double_huge_neg_exp.json":   true,
+	"i_number_huge_exp.json":              true,
+	"i_number_neg_int_huge_exp.json":      true,
+	"i_number_pos_double_huge_exp.json":   true,
+	"i_number_real_neg_overflow.json":     true,
+	"i_number_real_pos_overflow.json":     true,
+	"i_number_real_underflow.json":        true,
+	"i_number_too_big_neg_int.json":       true,
+	"i_number_too_big_pos_int.json":       true,
+	"i_number_very_big_negative_int.json": true,
+	"i_structure_500_nested_arrays.json":  true,
+
+	// unpaired surrogates can't be turned into utf-8
+	"i_object_key_lone_2nd_surrogate.json":                false,
+	"i_string_1st_surrogate_but_2nd_missing.json":         false,
+	"i_string_1st_valid_surrogate_2nd_invalid.json":       false,
+	"i_string_incomplete_surrogate_and_escape_valid.json": false,
+	"i_string_incomplete_surrogate_pair.json":             false,
+	"i_string_incomplete_surrogates_escape_valid.json":    false,
+	"i_string_invalid_lonely_surrogate.json":              false,
+	"i_string_invalid_surrogate.json":                     false,
+	"i_string_inverted_surrogates_U+1D11E.json":           false,
+	"i_string_lone_second_surrogate.json":                 false,
+
+	// input must be utf-8 without a byte order mark
+	"i_string_UTF-16LE_with_BOM.json":              false,
+	"i_string_UTF-8_invalid_sequence.json":         false,
+	"i_string_UTF8_surrogate_U+D800.json":          false,
+	"i_string_invalid_utf-8.json":                  false,
+	"i_string_iso_latin_1.json":                    false,
+	"i_string_lone_utf8_continuation_byte.json":    false,
+	"i_string_not_in_unicode_range.json":           false,
+	"i_string_overlong_sequence_2_bytes.json":      false,
+	"i_string_overlong_sequence_6_bytes.json":      false,
+	"i_string_overlong_sequence_6_bytes_null.json": false,
+	"i_string_truncated-utf-8.json":                false,
+	"i_string_utf16BE_no_BOM.json":                 false,
+	"i_string_utf16LE_no_BOM.json":                 false,
+	"i_structure_UTF-8_BOM_empty_object.json":      false,
+}
+
+func TestJSONTestSuite(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "JSONTestSuite", "test_parsing", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no JSONTestSuite files found")
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = ValidateJSON(input)
+
+			var accept bool
+			switch {
+			case strings.HasPrefix(name, "y_"):
+				accept = true
+			case strings.HasPrefix(name, "n_"):
+				accept = false
+			case strings.HasPrefix(name, "i_"):
+				var ok bool
+				accept, ok = implementationDefined[name]
+				if !ok {
+					t.Fatalf("no expected outcome in implementationDefined, ValidateJSON returned %v", err)
+				}
+			default:
+				t.Skip("not a y_, n_ or i_ file")
+			}
+
+			if accept && err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			} else if !accept && err == nil {
+				t.Errorf("expecting error but got <nil>")
+			}
+		})
+	}
+}
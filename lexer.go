@@ -0,0 +1,277 @@
+package gojson
+
+import (
+	"io"
+)
+
+type TokenKind int
+
+const (
+	TokenBeginObject TokenKind = iota
+	TokenEndObject
+	TokenBeginArray
+	TokenEndArray
+	TokenKey
+	TokenString
+	TokenNumber
+	TokenTrue
+	TokenFalse
+	TokenNull
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenBeginObject:
+		return "BeginObject"
+	case TokenEndObject:
+		return "EndObject"
+	case TokenBeginArray:
+		return "BeginArray"
+	case TokenEndArray:
+		return "EndArray"
+	case TokenKey:
+		return "Key"
+	case TokenString:
+		return "String"
+	case TokenNumber:
+		return "Number"
+	case TokenTrue:
+		return "True"
+	case TokenFalse:
+		return "False"
+	case TokenNull:
+		return "Null"
+	}
+	return "TokenKind(?)"
+}
+
+// Token is a single piece of a json document. Raw points into the
+// Lexer's input and Offset is where Raw starts in it. For a TokenKey
+// Raw is the quoted key without the ':' that follows it.
+type Token struct {
+	Kind   TokenKind
+	Raw    []byte
+	Offset int
+}
+
+// Lexer splits a json document into Tokens without building anything
+// and without allocating per token. Commas, colons and whitespace are
+// checked and skipped. Like ParseJSON the input may hold several
+// top-level values one after another.
+type Lexer struct {
+	b []byte
+	c int // b[:c] has been consumed
+
+	stack []container
+	small [16]container // backs stack until documents nest deeper
+	err   error         // a *SyntaxError stops the Lexer for good
+
+	opts      ParseOptions
+	truncated bool // b was cut down to opts.MaxBytes
+}
+
+func NewLexer(b []byte) *Lexer {
+	l := &Lexer{opts: DefaultParseOptions}
+	l.stack = l.small[:0]
+	l.Reset(b)
+	return l
+}
+
+// Reset makes l lex b from the start, reusing l's memory
+func (l *Lexer) Reset(b []byte) {
+	l.b, l.truncated = l.opts.truncate(b)
+	l.c = 0
+	l.stack = l.stack[:0]
+	l.err = nil
+}
+
+// Offset returns how much of the input has been consumed
+func (l *Lexer) Offset() int { return l.c }
+
+// Next returns the next Token. At the end of the input it returns
+// io.EOF, or a *SyntaxError if the input ended part way through an
+// object or array.
+func (l *Lexer) Next() (Token, error) {
+	if l.err != nil {
+		return Token{}, l.err
+	}
+
+	l.skipWhitespace()
+
+	if len(l.stack) == 0 {
+		if l.c == len(l.b) && l.truncated {
+			return Token{}, l.fail(l.c, "value", ErrMaxBytesExceeded)
+		}
+		if l.c == len(l.b) {
+			return Token{}, io.EOF
+		}
+		return l.value()
+	}
+
+	if l.c == len(l.b) {
+		return Token{}, l.fail(l.c, "value", ErrEOF)
+	}
+
+	top := &l.stack[len(l.stack)-1]
+	next := l.b[l.c]
+
+	if top.open == '{' {
+		switch top.state {
+		case stateEmpty, stateValue:
+			if next == '}' {
+				return l.end(TokenEndObject), nil
+			}
+			if top.state == stateValue {
+				if next != ',' {
+					return Token{}, l.fail(l.c, "object", ErrInvalidObjectClose)
+				}
+				if l.opts.MaxMembers > 0 && top.n == l.opts.MaxMembers {
+					return Token{}, l.fail(l.c, "members", ErrMaxMembersExceeded)
+				}
+				l.c++
+			}
+			return l.key()
+		}
+		// stateKey
+		return l.value()
+	}
+
+	switch top.state {
+	case stateEmpty:
+		if next == ']' {
+			return l.end(TokenEndArray), nil
+		}
+	case stateValue:
+		if next == ']' {
+			return l.end(TokenEndArray), nil
+		}
+		if next != ',' {
+			return Token{}, l.fail(l.c, "array", ErrInvalidArrayClose)
+		}
+		if l.opts.MaxMembers > 0 && top.n == l.opts.MaxMembers {
+			return Token{}, l.fail(l.c, "elements", ErrMaxMembersExceeded)
+		}
+		l.c++
+		l.skipWhitespace()
+	}
+	return l.value()
+}
+
+func (l *Lexer) skipWhitespace() {
+	_, n := ParseWhitespace(l.b[l.c:])
+	l.c += n
+}
+
+// key lexes a member's key and the ':' after it
+func (l *Lexer) key() (Token, error) {
+	// member
+	//     ws string ws ':' element
+	l.skipWhitespace()
+
+	start := l.c
+	p := parser{ParseOptions: l.opts}
+	s, n, err := p.str(l.b[l.c:])
+	if err != nil {
+		return Token{}, l.fail(l.c, "", err)
+	}
+	l.c += n
+
+	l.skipWhitespace()
+	if l.c == len(l.b) || l.b[l.c] != ':' {
+		return Token{}, l.fail(l.c, "member", ErrInvalidMemberMissingSep)
+	}
+	l.c++ // consume the ':'
+
+	l.stack[len(l.stack)-1].state = stateKey
+	return Token{Kind: TokenKey, Raw: s, Offset: start}, nil
+}
+
+// value lexes the first Token of a value
+func (l *Lexer) value() (Token, error) {
+	b := l.b[l.c:]
+	if len(b) == 0 {
+		return Token{}, l.fail(l.c, "value", ErrEOF)
+	}
+
+	t := Token{Offset: l.c}
+	var n int
+	var err error
+
+	switch b[0] {
+	case '{', '[':
+		if l.opts.MaxDepth > 0 && len(l.stack) >= l.opts.MaxDepth {
+			return Token{}, l.fail(l.c, "value", ErrMaxDepthExceeded)
+		}
+		t.Kind, n = TokenBeginObject, 1
+		if b[0] == '[' {
+			t.Kind = TokenBeginArray
+		}
+		l.stack = append(l.stack, container{open: b[0]})
+	case '"':
+		t.Kind = TokenString
+		p := parser{ParseOptions: l.opts}
+		_, n, err = p.str(b)
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		t.Kind = TokenNumber
+		_, n, err = ParseNumber(b)
+		if err == nil {
+			err = checkNumberEnd(b, n)
+		}
+		if err == nil && n == len(b) && l.truncated {
+			// the number may carry on past MaxBytes
+			return Token{}, l.fail(l.c+n, "number", ErrMaxBytesExceeded)
+		}
+	case 't', 'f':
+		t.Kind = TokenTrue
+		if b[0] == 'f' {
+			t.Kind = TokenFalse
+		}
+		_, n, err = ParseBoolean(b)
+	case 'n':
+		t.Kind = TokenNull
+		_, n, err = ParseNull(b)
+	default:
+		return Token{}, l.fail(l.c, "value", ErrUnsupported)
+	}
+	if err != nil {
+		return Token{}, l.fail(l.c, "", err)
+	}
+
+	t.Raw = b[:n]
+	l.c += n
+
+	if t.Kind != TokenBeginObject && t.Kind != TokenBeginArray {
+		l.decoded()
+	}
+	return t, nil
+}
+
+// end lexes the '}' or ']' closing the current object or array
+func (l *Lexer) end(kind TokenKind) Token {
+	t := Token{Kind: kind, Raw: l.b[l.c : l.c+1], Offset: l.c}
+	l.c++
+	l.stack = l.stack[:len(l.stack)-1]
+	l.decoded()
+	return t
+}
+
+// decoded records that a value has been lexed in the current container
+func (l *Lexer) decoded() {
+	if len(l.stack) > 0 {
+		l.stack[len(l.stack)-1].state = stateValue
+		l.stack[len(l.stack)-1].n++
+	}
+}
+
+// fail stops the Lexer with err positioned at l.b[off]. If expected is
+// empty err came from parsing l.b[off:] and only needs repositioning.
+func (l *Lexer) fail(off int, expected string, err error) error {
+	if expected != "" {
+		err = syntaxError(l.b, off, expected, err)
+	} else {
+		err = shiftError(l.b, off, err)
+	}
+	err = l.opts.bytesExceeded(l.b, l.truncated, err)
+	l.err = err
+	return err
+}
@@ -0,0 +1,181 @@
+package gojson
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// lex returns every token of input as "Kind Raw@Offset", one per line
+func lex(input []byte) (string, error) {
+	var out strings.Builder
+	l := NewLexer(input)
+	for {
+		tok, err := l.Next()
+		if err == io.EOF {
+			return out.String(), nil
+		}
+		if err != nil {
+			return out.String(), err
+		}
+		fmt.Fprintf(&out, "%s %s@%d\n", tok.Kind, tok.Raw, tok.Offset)
+	}
+}
+
+func TestLexer(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "scalar",
+			input:    ` -1.5e3 `,
+			expected: "Number -1.5e3@1\n",
+		},
+		{
+			name:  "object",
+			input: `{"a" : [1, true, false], "b": {"c": null}, "d": "str"}`,
+			expected: "BeginObject {@0\n" +
+				"Key \"a\"@1\n" +
+				"BeginArray [@7\n" +
+				"Number 1@8\n" +
+				"True true@11\n" +
+				"False false@17\n" +
+				"EndArray ]@22\n" +
+				"Key \"b\"@25\n" +
+				"BeginObject {@30\n" +
+				"Key \"c\"@31\n" +
+				"Null null@36\n" +
+				"EndObject }@40\n" +
+				"Key \"d\"@43\n" +
+				"String \"str\"@48\n" +
+				"EndObject }@53\n",
+		},
+		{
+			name:     "empty containers",
+			input:    "[ {}, [ ] ]",
+			expected: "BeginArray [@0\nBeginObject {@2\nEndObject }@3\nBeginArray [@6\nEndArray ]@8\nEndArray ]@10\n",
+		},
+		{
+			name:     "stream of documents",
+			input:    "1 \"two\"",
+			expected: "Number 1@0\nString \"two\"@2\n",
+		},
+		{
+			name:     "empty input",
+			input:    "   ",
+			expected: "",
+		},
+		{
+			name:     "trailing comma",
+			input:    `[1,]`,
+			expected: "BeginArray [@0\nNumber 1@1\n",
+			wantErr:  ErrUnsupported,
+		},
+		{
+			name:     "trailing comma in object",
+			input:    `{"a":1,}`,
+			expected: "BeginObject {@0\nKey \"a\"@1\nNumber 1@5\n",
+			wantErr:  ErrInvalidStringOpen,
+		},
+		{
+			name:     "mismatched close",
+			input:    `[1}`,
+			expected: "BeginArray [@0\nNumber 1@1\n",
+			wantErr:  ErrInvalidArrayClose,
+		},
+		{
+			name:     "missing colon",
+			input:    `{"a" 1}`,
+			expected: "BeginObject {@0\n",
+			wantErr:  ErrInvalidMemberMissingSep,
+		},
+		{
+			name:     "unterminated",
+			input:    `[1, [`,
+			expected: "BeginArray [@0\nNumber 1@1\nBeginArray [@4\n",
+			wantErr:  ErrEOF,
+		},
+		{
+			name:     "bad number",
+			input:    `[01]`,
+			expected: "BeginArray [@0\n",
+			wantErr:  ErrInvalidNumber,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			actual, err := lex([]byte(tc.input))
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("unexpected error: wanted %v got %v", tc.wantErr, err)
+			}
+			if tc.expected != actual {
+				t.Errorf("unexpected tokens: wanted\n%s\ngot\n%s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLexerErrorPosition(t *testing.T) {
+	_, err := lex(readFile(t, "badExample.json"))
+
+	var se *SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf("expecting *SyntaxError but got %v", err)
+	}
+	if se.Offset != 340 || se.Line != 15 || se.Column != 24 {
+		t.Errorf("unexpected position: got %d:%d (offset %d)", se.Line, se.Column, se.Offset)
+	}
+}
+
+func TestLexerDoesNotAllocate(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	l := NewLexer(example)
+	allocs := testing.AllocsPerRun(10, func() {
+		l.Reset(example)
+		for {
+			_, err := l.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("unexpected allocations: %v", allocs)
+	}
+}
+
+func BenchmarkLexer(b *testing.B) {
+	example := readFile(b, "example.json")
+
+	l := NewLexer(example)
+
+	b.SetBytes(int64(len(example)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Reset(example)
+		for {
+			_, err := l.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package gojson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// maxPointerDepth is how deep pointers, maps and slices may nest before
+// Marshal starts looking for cycles
+const maxPointerDepth = 1000
+
+// Marshal returns the json encoding of v. It is a drop-in for
+// encoding/json.Marshal: json tags, json.Marshaler,
+// encoding.TextMarshaler and json.Number are used the same way, map
+// keys are sorted, []byte is base64 and strings are escaped for
+// embedding in HTML. Errors are the same types encoding/json returns.
+func Marshal(v interface{}) ([]byte, error) {
+	e := encodeState{escapeHTML: true}
+	if err := e.value(reflect.ValueOf(v), false); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+// MarshalIndent is Marshal with each member and element on a new line
+// starting with prefix and indent repeated for each level of nesting
+func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	b, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendIndent(nil, b, prefix, indent)
+}
+
+// Encoder writes json values to an io.Writer, each followed by a
+// newline
+type Encoder struct {
+	w          io.Writer
+	prefix     string
+	indent     string
+	escapeHTML bool
+
+	buf []byte // reused for each value
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, escapeHTML: true}
+}
+
+// SetIndent makes the Encoder write values as MarshalIndent does
+func (enc *Encoder) SetIndent(prefix, indent string) {
+	enc.prefix, enc.indent = prefix, indent
+}
+
+// SetEscapeHTML sets whether '<', '>' and '&' in strings are escaped,
+// which they are by default
+func (enc *Encoder) SetEscapeHTML(on bool) {
+	enc.escapeHTML = on
+}
+
+// Encode writes the json encoding of v and a newline
+func (enc *Encoder) Encode(v interface{}) error {
+	e := encodeState{b: enc.buf[:0], escapeHTML: enc.escapeHTML}
+	if err := e.value(reflect.ValueOf(v), false); err != nil {
+		return err
+	}
+	b := e.b
+
+	if enc.prefix != "" || enc.indent != "" {
+		indented, err := appendIndent(nil, b, enc.prefix, enc.indent)
+		if err != nil {
+			return err
+		}
+		b = indented
+	}
+	b = append(b, '\n')
+
+	enc.buf = e.b
+	_, err := enc.w.Write(b)
+	return err
+}
+
+// encodeState appends the json encoding of Go values to b
+type encodeState struct {
+	b          []byte
+	escapeHTML bool
+
+	// pointers being encoded, once deep enough to be worth checking
+	// for cycles
+	depth int
+	seen  map[interface{}]bool
+}
+
+// value appends v. quoted is the ",string" option, which puts the
+// encoding of v in a string.
+func (e *encodeState) value(v reflect.Value, quoted bool) error {
+	if !v.IsValid() {
+		e.b = append(e.b, "null"...)
+		return nil
+	}
+
+	t := v.Type()
+	switch {
+	case t.Implements(marshalerType):
+		return e.marshaler(v)
+	case t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(marshalerType):
+		return e.marshaler(v.Addr())
+	case t.Implements(textMarshalerType):
+		return e.textMarshaler(v)
+	case t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(textMarshalerType):
+		return e.textMarshaler(v.Addr())
+	}
+
+	if quoted && v.Kind() == reflect.Ptr {
+		// ",string" applies to what the pointer points at
+		if v.IsNil() {
+			e.b = append(e.b, "null"...)
+			return nil
+		}
+		return e.value(v.Elem(), true)
+	}
+
+	if quoted {
+		e.b = append(e.b, '"')
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		e.b = strconv.AppendBool(e.b, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.b = strconv.AppendInt(e.b, v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.b = strconv.AppendUint(e.b, v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return &json.UnsupportedValueError{Value: v, Str: strconv.FormatFloat(f, 'g', -1, t.Bits())}
+		}
+		e.b = appendFloat(e.b, f, t.Bits())
+	case reflect.String:
+		if t == jsonNumberType {
+			n := v.String()
+			if n == "" {
+				n = "0" // what encoding/json does with an empty Number
+			}
+			if !Number(n).valid() {
+				return fmt.Errorf("json: invalid number literal %q", n)
+			}
+			e.b = append(e.b, n...)
+			break
+		}
+		if quoted {
+			// the string is encoded and then the encoding is quoted
+			s := appendString(nil, v.String(), e.escapeHTML)
+			e.b = appendString(e.b[:len(e.b)-1], string(s), e.escapeHTML)
+			return nil
+		}
+		e.b = appendString(e.b, v.String(), e.escapeHTML)
+	default:
+		if quoted {
+			e.b = e.b[:len(e.b)-1] // only literals are quoted
+		}
+		return e.composite(v)
+	}
+	if quoted {
+		e.b = append(e.b, '"')
+	}
+	return nil
+}
+
+// composite appends everything that isn't a literal
+func (e *encodeState) composite(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			e.b = append(e.b, "null"...)
+			return nil
+		}
+		return e.value(v.Elem(), false)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.b = append(e.b, "null"...)
+			return nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return err
+		}
+		defer leave()
+		return e.value(v.Elem(), false)
+
+	case reflect.Struct:
+		return e.structFields(v)
+
+	case reflect.Map:
+		if v.IsNil() {
+			e.b = append(e.b, "null"...)
+			return nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return err
+		}
+		defer leave()
+		return e.mapMembers(v)
+
+	case reflect.Slice:
+		if v.IsNil() {
+			e.b = append(e.b, "null"...)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := v.Bytes()
+			e.b = append(e.b, '"')
+			n := len(e.b)
+			e.b = append(e.b, make([]byte, base64.StdEncoding.EncodedLen(len(b)))...)
+			base64.StdEncoding.Encode(e.b[n:], b)
+			e.b = append(e.b, '"')
+			return nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return err
+		}
+		defer leave()
+		return e.elements(v)
+
+	case reflect.Array:
+		return e.elements(v)
+	}
+	return &json.UnsupportedTypeError{Type: v.Type()}
+}
+
+// enter records that the pointer, map or slice v is being encoded,
+// returning an error if it already is
+func (e *encodeState) enter(v reflect.Value) (func(), error) {
+	e.depth++
+	if e.depth <= maxPointerDepth {
+		return func() { e.depth-- }, nil
+	}
+
+	if e.seen == nil {
+		e.seen = map[interface{}]bool{}
+	}
+	// a slice is the same if it starts in the same place and has the
+	// same length
+	var key interface{} = v.Pointer()
+	if v.Kind() == reflect.Slice {
+		key = struct {
+			ptr uintptr
+			len int
+		}{v.Pointer(), v.Len()}
+	}
+	if e.seen[key] {
+		e.depth--
+		return nil, &json.UnsupportedValueError{Value: v, Str: fmt.Sprintf("encountered a cycle via %s", v.Type())}
+	}
+	e.seen[key] = true
+	return func() {
+		delete(e.seen, key)
+		e.depth--
+	}, nil
+}
+
+func (e *encodeState) structFields(v reflect.Value) error {
+	e.b = append(e.b, '{')
+	first := true
+
+fields:
+	for _, f := range cachedFields(v.Type()).list {
+		fv := v
+		for _, i := range f.index {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue fields // a nil embedded pointer has no fields
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(i)
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		if !first {
+			e.b = append(e.b, ',')
+		}
+		first = false
+		e.b = appendString(e.b, f.name, e.escapeHTML)
+		e.b = append(e.b, ':')
+		if err := e.value(fv, f.quoted); err != nil {
+			return err
+		}
+	}
+
+	e.b = append(e.b, '}')
+	return nil
+}
+
+func (e *encodeState) mapMembers(v reflect.Value) error {
+	type member struct {
+		key   string
+		value reflect.Value
+	}
+
+	members := make([]member, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		members = append(members, member{key, iter.Value()})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].key < members[j].key })
+
+	e.b = append(e.b, '{')
+	for i, m := range members {
+		if i > 0 {
+			e.b = append(e.b, ',')
+		}
+		e.b = appendString(e.b, m.key, e.escapeHTML)
+		e.b = append(e.b, ':')
+		if err := e.value(m.value, false); err != nil {
+			return err
+		}
+	}
+	e.b = append(e.b, '}')
+	return nil
+}
+
+// mapKey returns the json key for the map key k
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", &json.UnsupportedTypeError{Type: k.Type()}
+}
+
+func (e *encodeState) elements(v reflect.Value) error {
+	e.b = append(e.b, '[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			e.b = append(e.b, ',')
+		}
+		if err := e.value(v.Index(i), false); err != nil {
+			return err
+		}
+	}
+	e.b = append(e.b, ']')
+	return nil
+}
+
+// marshaler appends what the json.Marshaler v returns, after checking
+// it and compacting it
+func (e *encodeState) marshaler(v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		e.b = append(e.b, "null"...)
+		return nil
+	}
+	b, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err == nil {
+		e.b, err = Compact(e.b, b)
+	}
+	if err != nil {
+		return &json.MarshalerError{Type: v.Type(), Err: err}
+	}
+	return nil
+}
+
+// textMarshaler appends what the encoding.TextMarshaler v returns as a
+// string
+func (e *encodeState) textMarshaler(v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		e.b = append(e.b, "null"...)
+		return nil
+	}
+	b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return &json.MarshalerError{Type: v.Type(), Err: err}
+	}
+	e.b = appendString(e.b, string(b), e.escapeHTML)
+	return nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// appendFloat appends f the way encoding/json does, the shortest
+// decimal that parses back to f and an exponent only for very large or
+// small numbers
+func appendFloat(b []byte, f float64, bits int) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b = strconv.AppendFloat(b, f, format, -1, bits)
+	if format == 'e' {
+		// 1e-07 is written 1e-7
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
+
+const hex = "0123456789abcdef"
+
+// appendString appends s as a json string. Only what ParseCharacter
+// won't accept as is gets escaped, using the short escapes where
+// ParseEscape has one, plus '<', '>' and '&' if escapeHTML is set and
+// U+2028 and U+2029, which javascript doesn't allow in strings. Invalid
+// utf-8 is replaced with U+FFFD.
+func appendString(dst []byte, s string, escapeHTML bool) []byte {
+	dst = append(dst, '"')
+	start := 0 // s[start:i] is yet to be appended
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && (!escapeHTML || b != '<' && b != '>' && b != '&') {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\uFFFD"...)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xf])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,233 @@
+package gojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shout encodes as a json string in upper case, through its value
+type shout string
+
+func (s shout) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(string(s)))
+}
+
+// spaced returns its encoding with whitespace, through its pointer
+type spaced struct{ N int }
+
+func (s *spaced) MarshalJSON() ([]byte, error) {
+	return []byte("{ \"n\" :\n\t" + string(rune('0'+s.N)) + " }"), nil
+}
+
+// point is a map key through encoding.TextMarshaler
+type point struct{ X, Y int }
+
+func (p point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.X, p.Y)), nil
+}
+
+type failing struct{}
+
+func (failing) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("failing")
+}
+
+type omitted struct {
+	S string                 `json:"s,omitempty"`
+	I int                    `json:"i,omitempty"`
+	F float64                `json:"f,omitempty"`
+	B bool                   `json:"b,omitempty"`
+	P *int                   `json:"p,omitempty"`
+	M map[string]int         `json:"m,omitempty"`
+	L []int                  `json:"l,omitempty"`
+	X interface{}            `json:"x,omitempty"`
+	T time.Time              `json:"t,omitempty"` // structs are never empty
+	A [0]int                 `json:"a,omitempty"`
+	E map[string]interface{} `json:"e"`
+}
+
+func TestMarshal(t *testing.T) {
+	var example []person
+	if err := json.Unmarshal(readFile(t, "example.json"), &example); err != nil {
+		t.Fatal(err)
+	}
+	one := 1
+
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{name: "example", input: example},
+		{name: "nil", input: nil},
+		{name: "primitives", input: []interface{}{1, -2.5, "x", true, nil, uint8(7)}},
+		{name: "floats", input: []float64{0, -0.0, 1, 0.1, 1e20, 1e21, 1e-6, 1e-7, 123456789.125, math.MaxFloat64, math.SmallestNonzeroFloat64}},
+		{name: "float32", input: []float32{0.1, 1e21, 1e-7, 3.4e38}},
+		{name: "ints", input: []int64{0, math.MinInt64, math.MaxInt64}},
+		{name: "uints", input: []uint64{0, math.MaxUint64}},
+		{name: "escapes", input: "\"\\/\b\f\n\r\t\x00\x1f\x7f é 日本    <a href=\"x&y\">"},
+		{name: "invalid utf8", input: "a\xffb\xc3"},
+		{name: "bytes", input: []byte("hello, world")},
+		{name: "empty bytes", input: []byte{}},
+		{name: "nil bytes", input: []byte(nil)},
+		{name: "nil slice", input: []int(nil)},
+		{name: "empty slice", input: []int{}},
+		{name: "array", input: [3]string{"a", "b"}},
+		{name: "nil map", input: map[string]int(nil)},
+		{name: "map", input: map[string]int{"b": 2, "a": 1, "c": 3, "": 0}},
+		{name: "int keys", input: map[int]string{10: "ten", -1: "minus one", 2: "two"}},
+		{name: "uint keys", input: map[uint8]bool{255: true, 0: false}},
+		{name: "text keys", input: map[point]int{{2, 1}: 1, {10, 0}: 2}},
+		{name: "pointers", input: []*int{&one, nil}},
+		{name: "embedded", input: withEmbedded{Embedded: Embedded{A: 1, B: 2}, Inner: &Inner{C: []int{3}}, B: "b"}},
+		{name: "nil embedded", input: withEmbedded{Embedded: Embedded{A: 1}}},
+		{name: "quoted", input: quoted{I: 1, F: 0.5, B: true, S: `a"b`, P: &one}},
+		{name: "quoted nil", input: quoted{}},
+		{name: "omitempty", input: omitted{}},
+		{name: "not omitted", input: omitted{S: "s", I: 1, F: 1, B: true, P: &one, M: map[string]int{}, L: []int{0}, X: 0}},
+		{name: "marshaler", input: []shout{"a", "b"}},
+		{name: "marshaler map", input: map[string]shout{"k": "v"}},
+		{name: "pointer marshaler", input: &struct{ S spaced }{spaced{N: 3}}},
+		{name: "pointer marshaler not addressable", input: struct{ S spaced }{spaced{N: 3}}},
+		{name: "nil marshaler", input: []*shout{nil}},
+		{name: "nil marshaler interface", input: struct{ M json.Marshaler }{}},
+		{name: "text marshaler", input: []net.IP{net.IPv4(127, 0, 0, 1)}},
+		{name: "time", input: time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)},
+		{name: "number", input: []json.Number{"1.50", "-0e+1", ""}},
+		{name: "raw message", input: map[string]json.RawMessage{"r": json.RawMessage(` [ 1 ,2 ] `)}},
+		{name: "interface map", input: map[string]interface{}{"o": map[string]interface{}{"a": []interface{}{}}, "e": struct{}{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+			}
+
+			expected, err = json.MarshalIndent(tt.input, "> ", "\t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err = MarshalIndent(tt.input, "> ", "\t")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("indented: expected\n%s\ngot\n%s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	type cycle struct {
+		Next *cycle
+	}
+	c := &cycle{}
+	c.Next = c
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		wantErr interface{} // pointer to the error type expected
+	}{
+		{name: "chan", input: make(chan int), wantErr: new(*json.UnsupportedTypeError)},
+		{name: "func field", input: struct{ F func() }{}, wantErr: new(*json.UnsupportedTypeError)},
+		{name: "complex", input: complex(1, 2), wantErr: new(*json.UnsupportedTypeError)},
+		{name: "bad key", input: map[[2]int]int{{1, 2}: 3}, wantErr: new(*json.UnsupportedTypeError)},
+		{name: "NaN", input: math.NaN(), wantErr: new(*json.UnsupportedValueError)},
+		{name: "Inf", input: []float64{math.Inf(-1)}, wantErr: new(*json.UnsupportedValueError)},
+		{name: "cycle", input: c, wantErr: new(*json.UnsupportedValueError)},
+		{name: "marshaler error", input: failing{}, wantErr: new(*json.MarshalerError)},
+		{name: "marshaler invalid", input: json.RawMessage(`{`), wantErr: new(*json.MarshalerError)},
+		{name: "bad number", input: json.Number("01"), wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, expected := json.Marshal(tt.input)
+			if expected == nil {
+				t.Fatal("encoding/json has no error")
+			}
+			_, err := Marshal(tt.input)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.As(err, tt.wantErr) {
+				t.Errorf("expected %T, got %T: %v", reflect.ValueOf(tt.wantErr).Elem().Interface(), err, err)
+			}
+		})
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []interface{}
+		prefix   string
+		indent   string
+		noEscape bool
+	}{
+		{name: "values", values: []interface{}{1, "<a>", map[string]int{"b": 1, "a": 2}, nil}},
+		{name: "indented", values: []interface{}{[]int{1, 2}, map[string][]int{"a": {}}}, prefix: "#", indent: "  "},
+		{name: "no html escaping", values: []interface{}{"<a&b>", []string{">"}}, noEscape: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected, actual bytes.Buffer
+			std, enc := json.NewEncoder(&expected), NewEncoder(&actual)
+			std.SetIndent(tt.prefix, tt.indent)
+			enc.SetIndent(tt.prefix, tt.indent)
+			std.SetEscapeHTML(!tt.noEscape)
+			enc.SetEscapeHTML(!tt.noEscape)
+
+			for _, v := range tt.values {
+				if err := std.Encode(v); err != nil {
+					t.Fatal(err)
+				}
+				if err := enc.Encode(v); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if actual.String() != expected.String() {
+				t.Errorf("expected\n%s\ngot\n%s", expected.String(), actual.String())
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	var v interface{}
+	if err := Unmarshal(example, &v); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again interface{}
+	if err := Unmarshal(b, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, again) {
+		t.Error("round trip changed the value")
+	}
+}
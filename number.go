@@ -0,0 +1,283 @@
+package gojson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// maxBigIntExponent keeps BigInt from allocating without bound for
+// short inputs like 1e999999999
+const maxBigIntExponent = 1 << 16
+
+// Decimal is the exact value of a Number, Coefficient × 10^Exponent.
+// Decimals from Number.Decimal are normalized: the coefficient has no
+// trailing zeros and zero is 0×10^0, so numbers that are equal in
+// value, like 1.50 and 15e-1, have equal Decimals.
+type Decimal struct {
+	Coefficient *big.Int
+	Exponent    int
+}
+
+// String returns d as a json number
+func (d Decimal) String() string {
+	if d.Exponent == 0 {
+		return d.Coefficient.String()
+	}
+	return d.Coefficient.String() + "e" + strconv.Itoa(d.Exponent)
+}
+
+// rat returns d as a fraction
+func (d Decimal) rat() *big.Rat {
+	r := new(big.Rat).SetInt(d.Coefficient)
+	if d.Exponent >= 0 {
+		return r.Mul(r, new(big.Rat).SetInt(pow10(d.Exponent)))
+	}
+	return r.Quo(r, new(big.Rat).SetInt(pow10(-d.Exponent)))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// parts splits n into its int, frac and exp. It fails unless all of n
+// is a single json number.
+func (n Number) parts() (Int, Frac, Exp, error) {
+	i, c, err := ParseInt(n)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	f, consumed := ParseFrac(n[c:])
+	c += consumed
+	e, consumed := ParseExp(n[c:])
+	c += consumed
+
+	if c != len(n) {
+		return nil, nil, nil, syntaxError(n, c, "number", ErrInvalidNumber)
+	}
+	return i, f, e, nil
+}
+
+func (n Number) rangeError() error {
+	return fmt.Errorf("%w: %s", ErrNumberRange, n)
+}
+
+// Decimal returns the exact value of n
+func (n Number) Decimal() (Decimal, error) {
+	i, f, e, err := n.parts()
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	var exp int64
+	if len(e) > 0 {
+		// skip the 'e', ParseInt takes care of the sign
+		if exp, err = strconv.ParseInt(string(e[1:]), 10, 32); err != nil {
+			return Decimal{}, n.rangeError()
+		}
+	}
+
+	digits := make([]byte, 0, len(i)+len(f))
+	digits = append(digits, i...)
+	if len(f) > 0 {
+		digits = append(digits, f[1:]...) // skip the '.'
+		exp -= int64(len(f) - 1)
+	}
+
+	// normalize
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+		exp++
+	}
+	if len(bytes.Trim(digits, "-0")) == 0 {
+		return Decimal{Coefficient: new(big.Int)}, nil
+	}
+	if exp < -1<<31 || exp > 1<<31-1 {
+		return Decimal{}, n.rangeError()
+	}
+
+	coefficient, _ := new(big.Int).SetString(string(digits), 10)
+	return Decimal{Coefficient: coefficient, Exponent: int(exp)}, nil
+}
+
+// normalized returns n written the way Decimal.String writes it, the
+// coefficient without trailing zeros and the exponent, but with the
+// exponent kept as a big.Int so that it has no range to be out of.
+// Numbers with the same value are normalized the same.
+func (n Number) normalized() (string, error) {
+	i, f, e, err := n.parts()
+	if err != nil {
+		return "", err
+	}
+
+	exp := new(big.Int)
+	if len(e) > 0 {
+		exp.SetString(string(e[1:]), 10) // skip the 'e', SetString takes the sign
+	}
+
+	digits := make([]byte, 0, len(i)+len(f))
+	digits = append(digits, i...)
+	if len(f) > 0 {
+		digits = append(digits, f[1:]...) // skip the '.'
+		exp.Sub(exp, big.NewInt(int64(len(f)-1)))
+	}
+
+	zeros := 0
+	for zeros < len(digits) && digits[len(digits)-1-zeros] == '0' {
+		zeros++
+	}
+	digits = digits[:len(digits)-zeros]
+	if len(bytes.Trim(digits, "-0")) == 0 {
+		return "0", nil
+	}
+	exp.Add(exp, big.NewInt(int64(zeros)))
+
+	coefficient, _ := new(big.Int).SetString(string(digits), 10)
+	if exp.Sign() == 0 {
+		return coefficient.String(), nil
+	}
+	return coefficient.String() + "e" + exp.String(), nil
+}
+
+// BigInt returns n as an integer. Numbers like 1e3 and 2.50e1 are
+// integers, 1.5 is not.
+func (n Number) BigInt() (*big.Int, error) {
+	return n.bigInt(maxBigIntExponent)
+}
+
+// bigInt is BigInt for numbers with an exponent of at most max once
+// normalized, so 1e999999999 fails before it is expanded
+func (n Number) bigInt(max int) (*big.Int, error) {
+	d, err := n.Decimal()
+	if err != nil {
+		return nil, err
+	}
+	if d.Exponent < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrParseInteger, n)
+	}
+	if d.Exponent > max {
+		return nil, n.rangeError()
+	}
+	if d.Exponent == 0 {
+		return d.Coefficient, nil
+	}
+	return d.Coefficient.Mul(d.Coefficient, pow10(d.Exponent)), nil
+}
+
+// isPlainInt reports whether n has no frac or exp, which leaves
+// strconv to do the work
+func (n Number) isPlainInt() bool {
+	return bytes.IndexAny(n, ".eE") == -1
+}
+
+// Int64 returns n as an int64. It returns an ErrParseInteger error
+// when n is not an integer and an ErrNumberRange error when it doesn't
+// fit.
+func (n Number) Int64() (int64, error) {
+	if _, _, _, err := n.parts(); err != nil {
+		return 0, err
+	}
+	if n.isPlainInt() {
+		i, err := strconv.ParseInt(string(n), 10, 64)
+		if err != nil {
+			return 0, n.rangeError()
+		}
+		return i, nil
+	}
+
+	i, err := n.bigInt(19)
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, n.rangeError()
+	}
+	return i.Int64(), nil
+}
+
+// Uint64 returns n as a uint64, see Int64
+func (n Number) Uint64() (uint64, error) {
+	if _, _, _, err := n.parts(); err != nil {
+		return 0, err
+	}
+	if n.isPlainInt() && n[0] != '-' {
+		u, err := strconv.ParseUint(string(n), 10, 64)
+		if err != nil {
+			return 0, n.rangeError()
+		}
+		return u, nil
+	}
+
+	i, err := n.bigInt(20)
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsUint64() {
+		return 0, n.rangeError()
+	}
+	return i.Uint64(), nil
+}
+
+// Int is the same as Int64
+func (n Number) Int() (int64, error) {
+	return n.Int64()
+}
+
+// Float64 returns the float64 nearest to n. Numbers too large for a
+// float64 return ±Inf and an ErrNumberRange error, numbers too small
+// round to zero without one. Use IsFloat64 to find out whether
+// anything was lost.
+func (n Number) Float64() (float64, error) {
+	if _, _, _, err := n.parts(); err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return f, n.rangeError()
+	}
+	return f, err
+}
+
+// BigFloat returns n with enough precision to keep all of its
+// digits. Decimal fractions like 0.1 have no exact binary value and
+// are still rounded, use Decimal when that matters.
+func (n Number) BigFloat() (*big.Float, error) {
+	if _, _, _, err := n.parts(); err != nil {
+		return nil, err
+	}
+	// a decimal digit takes less than 4 bits
+	prec := uint(4*len(n) + 64)
+	f, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, n.rangeError()
+	}
+	return f, nil
+}
+
+// IsInt64 reports whether n is an integer that fits in an int64
+func (n Number) IsInt64() bool {
+	_, err := n.Int64()
+	return err == nil
+}
+
+// IsFloat64 reports whether n has an exact float64 value. 0.5 and 1e3
+// do, 0.1 and 1e400 don't.
+func (n Number) IsFloat64() bool {
+	f, err := n.Float64()
+	if err != nil {
+		return false
+	}
+	d, err := n.Decimal()
+	if err != nil {
+		return false
+	}
+	if f == 0 || d.Coefficient.Sign() == 0 {
+		// 1e-400 underflows to zero
+		return f == 0 && d.Coefficient.Sign() == 0
+	}
+	// f is finite so d is within a few hundred orders of magnitude
+	// of 1, and its fraction is cheap to build
+	return d.rat().Cmp(new(big.Rat).SetFloat64(f)) == 0
+}
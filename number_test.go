@@ -0,0 +1,183 @@
+package gojson
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNumberInt64(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Number
+		expected int64
+		wantErr  error
+	}{
+		{name: "zero", input: Number(`0`), expected: 0},
+		{name: "negative zero", input: Number(`-0`), expected: 0},
+		{name: "negative", input: Number(`-42`), expected: -42},
+		{name: "exponent", input: Number(`1e3`), expected: 1000},
+		{name: "fraction that is an integer", input: Number(`2.50e1`), expected: 25},
+		{name: "trailing zeros", input: Number(`7.000`), expected: 7},
+		{name: "max", input: Number(`9223372036854775807`), expected: math.MaxInt64},
+		{name: "min", input: Number(`-9223372036854775808`), expected: math.MinInt64},
+		{name: "min with an exponent", input: Number(`-9.223372036854775808e18`), expected: math.MinInt64},
+		{name: "overflow", input: Number(`9223372036854775808`), wantErr: ErrNumberRange},
+		{name: "overflow with an exponent", input: Number(`1e19`), wantErr: ErrNumberRange},
+		{name: "huge exponent", input: Number(`1e999999999`), wantErr: ErrNumberRange},
+		{name: "exponent past int32", input: Number(`1e9999999999`), wantErr: ErrNumberRange},
+		{name: "fraction", input: Number(`1.5`), wantErr: ErrParseInteger},
+		{name: "tiny", input: Number(`1e-999999999`), wantErr: ErrParseInteger},
+		{name: "not a number", input: Number(`1x`), wantErr: ErrInvalidNumber},
+		{name: "leading zero", input: Number(`01`), wantErr: ErrInvalidNumber},
+		{name: "plus sign", input: Number(`+1`), wantErr: ErrUnexpectedChar},
+		{name: "empty", input: Number(``), wantErr: ErrEOF},
+	}
+
+	for _, tc := range tests {
+		actual, err := tc.input.Int64()
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: expected %v: got %d, %v", tc.name, tc.wantErr, actual, err)
+			}
+			if tc.input.IsInt64() {
+				t.Errorf("%s: expected IsInt64 to be false", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if actual != tc.expected {
+			t.Errorf("%s: expected %d: got %d", tc.name, tc.expected, actual)
+		}
+		if !tc.input.IsInt64() {
+			t.Errorf("%s: expected IsInt64 to be true", tc.name)
+		}
+	}
+}
+
+func TestNumberUint64(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Number
+		expected uint64
+		wantErr  error
+	}{
+		{name: "zero", input: Number(`0`), expected: 0},
+		{name: "negative zero", input: Number(`-0.0`), expected: 0},
+		{name: "max", input: Number(`18446744073709551615`), expected: math.MaxUint64},
+		{name: "max with an exponent", input: Number(`1.8446744073709551615e19`), expected: math.MaxUint64},
+		{name: "overflow", input: Number(`18446744073709551616`), wantErr: ErrNumberRange},
+		{name: "negative", input: Number(`-1`), wantErr: ErrNumberRange},
+		{name: "fraction", input: Number(`0.5`), wantErr: ErrParseInteger},
+	}
+
+	for _, tc := range tests {
+		actual, err := tc.input.Uint64()
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: expected %v: got %d, %v", tc.name, tc.wantErr, actual, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if actual != tc.expected {
+			t.Errorf("%s: expected %d: got %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestNumberFloat64(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Number
+		expected float64
+		exact    bool
+		wantErr  error
+	}{
+		{name: "integer", input: Number(`3`), expected: 3, exact: true},
+		{name: "half", input: Number(`-0.5`), expected: -0.5, exact: true},
+		{name: "tenth", input: Number(`0.1`), expected: 0.1},
+		{name: "exponent", input: Number(`1.25E+2`), expected: 125, exact: true},
+		{name: "2^53 + 1", input: Number(`9007199254740993`), expected: 9007199254740992},
+		{name: "max is the shortest round trip, not exact", input: Number(`1.7976931348623157e308`), expected: math.MaxFloat64},
+		{name: "2^53", input: Number(`9007199254740992`), expected: 9007199254740992, exact: true},
+		{name: "underflow", input: Number(`1e-400`), expected: 0},
+		{name: "zero with a big exponent", input: Number(`0e400`), expected: 0, exact: true},
+		{name: "overflow", input: Number(`1e400`), expected: math.Inf(1), wantErr: ErrNumberRange},
+		{name: "negative overflow", input: Number(`-1e400`), expected: math.Inf(-1), wantErr: ErrNumberRange},
+		{name: "not a number", input: Number(`1.`), wantErr: ErrInvalidNumber},
+	}
+
+	for _, tc := range tests {
+		actual, err := tc.input.Float64()
+		if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: expected %v: got %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if tc.wantErr == nil && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if actual != tc.expected {
+			t.Errorf("%s: expected %v: got %v", tc.name, tc.expected, actual)
+		}
+		if exact := tc.input.IsFloat64(); exact != tc.exact {
+			t.Errorf("%s: expected IsFloat64 to be %v: got %v", tc.name, tc.exact, exact)
+		}
+	}
+}
+
+func TestNumberDecimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Number
+		expected string
+	}{
+		{name: "integer", input: Number(`120`), expected: "12e1"},
+		{name: "cents", input: Number(`19.99`), expected: "1999e-2"},
+		{name: "equal values are equal", input: Number(`15e-1`), expected: "15e-1"},
+		{name: "trailing zeros", input: Number(`1.500`), expected: "15e-1"},
+		{name: "zero", input: Number(`-0.000e7`), expected: "0"},
+		{name: "more digits than a float64", input: Number(`12345678901234567890.123456789`), expected: "12345678901234567890123456789e-9"},
+		{name: "exponent", input: Number(`-4.2E-7`), expected: "-42e-8"},
+	}
+
+	for _, tc := range tests {
+		d, err := tc.input.Decimal()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if actual := d.String(); actual != tc.expected {
+			t.Errorf("%s: expected %s: got %s", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestNumberBig(t *testing.T) {
+	i, err := Number(`123456789012345678901234567890e3`).BigInt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := i.String(); actual != "123456789012345678901234567890000" {
+		t.Errorf("expected 123456789012345678901234567890000: got %s", actual)
+	}
+
+	if _, err := Number(`1e99999999`).BigInt(); !errors.Is(err, ErrNumberRange) {
+		t.Errorf("expected %v: got %v", ErrNumberRange, err)
+	}
+
+	f, err := Number(`12345678901234567890.5`).BigFloat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := f.Text('f', 1); actual != "12345678901234567890.5" {
+		t.Errorf("expected 12345678901234567890.5: got %s", actual)
+	}
+}
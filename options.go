@@ -0,0 +1,142 @@
+package gojson
+
+import (
+	"fmt"
+	"io"
+)
+
+var (
+	ErrMaxDepthExceeded        = fmt.Errorf("limit: maximum depth exceeded")
+	ErrMaxBytesExceeded        = fmt.Errorf("limit: maximum bytes exceeded")
+	ErrMaxStringLengthExceeded = fmt.Errorf("limit: maximum string length exceeded")
+	ErrMaxMembersExceeded      = fmt.Errorf("limit: maximum members exceeded")
+)
+
+// ParseOptions limits what a parse will accept, so that untrusted
+// input can't exhaust the stack or memory. A limit of 0 means no limit.
+// Exceeding a limit is reported as a *SyntaxError wrapping one of the
+// ErrMax* errors, positioned at the byte that went over.
+type ParseOptions struct {
+	// MaxDepth is how deeply objects and arrays may nest, "[]" has a
+	// depth of 1
+	MaxDepth int
+
+	// MaxBytes is how much input may be read. For a Decoder this counts
+	// every value it reads from the stream.
+	MaxBytes int
+
+	// MaxStringLength is how many bytes may be between the quotes of a
+	// string, keys included. Escapes count as the bytes they are
+	// written with.
+	MaxStringLength int
+
+	// MaxMembers is how many members an object, or elements an array,
+	// may have
+	MaxMembers int
+}
+
+// DefaultParseOptions are enforced by the package level Parse
+// functions, NewDecoder, NewLexer and ParseWithHandler. It only limits
+// depth, deep enough for any sane document while keeping the recursive
+// Parse functions well clear of the goroutine stack limit.
+var DefaultParseOptions = ParseOptions{
+	MaxDepth: 10000,
+}
+
+// ParseJSON is ParseJSON enforcing o
+func (o ParseOptions) ParseJSON(b []byte) ([]byte, int, error) {
+	// json
+	//     element
+
+	in, truncated := o.truncate(b)
+
+	p := parser{ParseOptions: o}
+	_, c, err := p.element(in)
+	if err != nil {
+		return nil, 0, o.bytesExceeded(in, truncated, err) // already positioned relative to b
+	}
+	if truncated && c == len(in) {
+		// the document may well carry on past MaxBytes
+		return nil, 0, syntaxError(b, c, "end of input", ErrMaxBytesExceeded)
+	}
+
+	return b[:c], c, nil
+}
+
+// ValidateJSON is ValidateJSON enforcing o
+func (o ParseOptions) ValidateJSON(b []byte) error {
+	_, c, err := o.ParseJSON(b)
+	if err != nil {
+		return err
+	}
+
+	if c != len(b) {
+		return syntaxError(b, c, "end of input", ErrTrailingData)
+	}
+
+	return nil
+}
+
+// NewDecoder is NewDecoder enforcing o
+func (o ParseOptions) NewDecoder(r io.Reader) *Decoder {
+	d := NewDecoder(r)
+	d.opts = o
+	return d
+}
+
+// NewLexer is NewLexer enforcing o
+func (o ParseOptions) NewLexer(b []byte) *Lexer {
+	l := NewLexer(b)
+	l.opts = o
+	l.Reset(b)
+	return l
+}
+
+// ParseWithHandler is ParseWithHandler enforcing o
+func (o ParseOptions) ParseWithHandler(b []byte, h Handler) (int, error) {
+	var l Lexer
+	l.stack = l.small[:0]
+	l.opts = o
+	l.Reset(b)
+	return parseWithHandler(&l, h)
+}
+
+// truncate cuts b down to MaxBytes
+func (o ParseOptions) truncate(b []byte) ([]byte, bool) {
+	if o.MaxBytes > 0 && len(b) > o.MaxBytes {
+		return b[:o.MaxBytes], true
+	}
+	return b, false
+}
+
+// bytesExceeded turns an error from running out of a truncated input
+// into ErrMaxBytesExceeded
+func (o ParseOptions) bytesExceeded(in []byte, truncated bool, err error) error {
+	if e, ok := err.(*SyntaxError); ok && truncated && e.Offset == len(in) {
+		e.Err = ErrMaxBytesExceeded
+		e.Expected = "end of input"
+	}
+	return err
+}
+
+// parser carries the options and the current depth through the
+// recursive Parse functions
+type parser struct {
+	ParseOptions
+	depth int
+}
+
+// push enters the object or array starting at b[0]
+func (p *parser) push(b []byte) error {
+	p.depth++
+	if p.MaxDepth > 0 && p.depth > p.MaxDepth {
+		p.depth--
+		return syntaxError(b, 0, "value", ErrMaxDepthExceeded)
+	}
+	return nil
+}
+
+// pop leaves the object or array entered by push
+func (p *parser) pop() {
+	p.depth--
+}
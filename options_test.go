@@ -0,0 +1,205 @@
+package gojson
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// every way of parsing input, each should enforce the options the same
+var parsePaths = map[string]func(o ParseOptions, input []byte) error{
+	"ValidateJSON": func(o ParseOptions, input []byte) error {
+		return o.ValidateJSON(input)
+	},
+	"Decoder": func(o ParseOptions, input []byte) error {
+		d := o.NewDecoder(iotest.OneByteReader(bytes.NewReader(input)))
+		for {
+			more, err := d.More()
+			if err != nil || !more {
+				return err
+			}
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	},
+	"Lexer": func(o ParseOptions, input []byte) error {
+		l := o.NewLexer(input)
+		for {
+			_, err := l.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	},
+	"ParseWithHandler": func(o ParseOptions, input []byte) error {
+		_, err := o.ParseWithHandler(input, BaseHandler{})
+		return err
+	},
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  ParseOptions
+		input []byte
+
+		// expected
+		err    error
+		offset int
+	}{
+		{
+			name:  "no limits",
+			input: []byte(`{"a": [1, 2, {"b": "cdef"}]}`),
+		},
+		{
+			name:  "within max depth",
+			opts:  ParseOptions{MaxDepth: 3},
+			input: []byte(`[[[1]]]`),
+		},
+		{
+			name:   "max depth exceeded",
+			opts:   ParseOptions{MaxDepth: 2},
+			input:  []byte(`[[[1]]]`),
+			err:    ErrMaxDepthExceeded,
+			offset: 2,
+		},
+		{
+			name:   "max depth exceeded by an object",
+			opts:   ParseOptions{MaxDepth: 1},
+			input:  []byte(`{"a": {}}`),
+			err:    ErrMaxDepthExceeded,
+			offset: 6,
+		},
+		{
+			name:  "within max members",
+			opts:  ParseOptions{MaxMembers: 3},
+			input: []byte(`[1,2,3]`),
+		},
+		{
+			name:   "max elements exceeded",
+			opts:   ParseOptions{MaxMembers: 2},
+			input:  []byte(`[1,2,3]`),
+			err:    ErrMaxMembersExceeded,
+			offset: 4,
+		},
+		{
+			name:   "max members exceeded",
+			opts:   ParseOptions{MaxMembers: 1},
+			input:  []byte(`{"a":1,"b":2}`),
+			err:    ErrMaxMembersExceeded,
+			offset: 6,
+		},
+		{
+			name:  "within max string length",
+			opts:  ParseOptions{MaxStringLength: 4},
+			input: []byte(`{"abcd":"é"}`),
+		},
+		{
+			name:   "max string length exceeded",
+			opts:   ParseOptions{MaxStringLength: 3},
+			input:  []byte(`["abc","abcd"]`),
+			err:    ErrMaxStringLengthExceeded,
+			offset: 11,
+		},
+		{
+			name:   "max string length exceeded by a key",
+			opts:   ParseOptions{MaxStringLength: 3},
+			input:  []byte(`{"abcd":1}`),
+			err:    ErrMaxStringLengthExceeded,
+			offset: 5,
+		},
+		{
+			name:  "within max bytes",
+			opts:  ParseOptions{MaxBytes: 7},
+			input: []byte(`[1,2,3]`),
+		},
+		{
+			name:   "max bytes exceeded",
+			opts:   ParseOptions{MaxBytes: 5},
+			input:  []byte(`[1,2,3]`),
+			err:    ErrMaxBytesExceeded,
+			offset: 5,
+		},
+		{
+			name:   "max bytes exceeded part way through a number",
+			opts:   ParseOptions{MaxBytes: 3},
+			input:  []byte(`12345`),
+			err:    ErrMaxBytesExceeded,
+			offset: 3,
+		},
+		{
+			name:   "max bytes exceeded part way through a string",
+			opts:   ParseOptions{MaxBytes: 4},
+			input:  []byte(`"abcdef"`),
+			err:    ErrMaxBytesExceeded,
+			offset: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		for path, parse := range parsePaths {
+			err := parse(tc.opts, tc.input)
+			if tc.err == nil {
+				if err != nil {
+					t.Errorf("%s: %s: unexpected error: %v", tc.name, path, err)
+				}
+				continue
+			}
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%s: %s: expected %v: got %v", tc.name, path, tc.err, err)
+				continue
+			}
+			var se *SyntaxError
+			if !errors.As(err, &se) {
+				t.Errorf("%s: %s: expected a *SyntaxError: got %T", tc.name, path, err)
+				continue
+			}
+			if se.Offset != tc.offset {
+				t.Errorf("%s: %s: expected offset %d: got %d", tc.name, path, tc.offset, se.Offset)
+			}
+		}
+	}
+}
+
+func TestDefaultParseOptionsDeepNesting(t *testing.T) {
+	// far deeper than the goroutine stack would allow the recursive
+	// Parse functions to go
+	input := bytes.Repeat([]byte{'['}, 1000000)
+
+	for path, parse := range parsePaths {
+		err := parse(DefaultParseOptions, input)
+		var se *SyntaxError
+		if !errors.Is(err, ErrMaxDepthExceeded) || !errors.As(err, &se) {
+			t.Errorf("%s: expected %v: got %v", path, ErrMaxDepthExceeded, err)
+			continue
+		}
+		if se.Offset != DefaultParseOptions.MaxDepth {
+			t.Errorf("%s: expected offset %d: got %d", path, DefaultParseOptions.MaxDepth, se.Offset)
+		}
+	}
+}
+
+func TestDecoderValueDepth(t *testing.T) {
+	// the arrays the Decoder is already inside of count towards the
+	// depth of a Value
+	d := ParseOptions{MaxDepth: 2}.NewDecoder(bytes.NewReader([]byte(`[[[1]]]`)))
+	if err := d.BeginArray(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := d.Value()
+	var se *SyntaxError
+	if !errors.Is(err, ErrMaxDepthExceeded) || !errors.As(err, &se) {
+		t.Fatalf("expected %v: got %v", ErrMaxDepthExceeded, err)
+	}
+	if se.Offset != 2 {
+		t.Errorf("expected offset 2: got %d", se.Offset)
+	}
+}
@@ -0,0 +1,131 @@
+package gojson
+
+import (
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidPointer = fmt.Errorf("pointer: invalid json pointer")
+
+// Pointer is a json pointer (RFC 6901) split into its reference
+// tokens, with their ~0 and ~1 escapes decoded. The empty Pointer
+// refers to the whole document.
+type Pointer []string
+
+// ParsePointer parses a json pointer such as "/0/name/first"
+func ParsePointer(s string) (Pointer, error) {
+	// json-pointer
+	//     *( "/" reference-token )
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("%w: %q must start with '/'", ErrInvalidPointer, s)
+	}
+
+	p := strings.Split(s[1:], "/")
+	for i, tok := range p {
+		if !strings.Contains(tok, "~") {
+			continue
+		}
+		// reference-token
+		//     *( unescaped / escaped )
+		// escaped
+		//     "~" ( "0" / "1" )
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j+1 == len(tok) || (tok[j+1] != '0' && tok[j+1] != '1')) {
+				return nil, fmt.Errorf("%w: %q has a '~' not followed by '0' or '1'", ErrInvalidPointer, s)
+			}
+		}
+		// ~1 first so that "~01" becomes "~1" and not "/"
+		p[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return p, nil
+}
+
+// String returns p as a json pointer with '~' and '/' escaped
+func (p Pointer) String() string {
+	var b strings.Builder
+	for _, tok := range p {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// Get returns the value p refers to in data and its offset, so it
+// takes up data[offset:offset+len(v)]. Like Get it only scans as much
+// of data as it has to.
+func (p Pointer) Get(data []byte) (Value, int, error) {
+	_, c := ParseWhitespace(data)
+
+	for i, tok := range p {
+		var err error
+		switch {
+		case c < len(data) && data[c] == '{':
+			c, err = getMember(data, c, tok)
+		case c < len(data) && data[c] == '[':
+			err = ErrNotFound
+			if n, ok := arrayIndex(tok); ok {
+				c, err = getElement(data, c, n)
+			}
+		default:
+			err = ErrNotFound
+		}
+		if err == ErrNotFound {
+			return nil, 0, fmt.Errorf("%w: %s", err, p[:i+1])
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	v, _, err := ParseValue(data[c:])
+	if err != nil {
+		return nil, 0, shiftError(data, c, err)
+	}
+	return v, c, nil
+}
+
+// Find returns the Node p refers to in the tree n
+func (p Pointer) Find(n *Node) (*Node, error) {
+	for i, tok := range p {
+		var next *Node
+		switch n.Kind {
+		case KindObject:
+			for j := range n.Members {
+				if n.Members[j].Key.equal(tok) {
+					next = &n.Members[j].Value
+					break
+				}
+			}
+		case KindArray:
+			if e, ok := arrayIndex(tok); ok && e < len(n.Elements) {
+				next = &n.Elements[e]
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, p[:i+1])
+		}
+		n = next
+	}
+	return n, nil
+}
+
+// arrayIndex returns the array index tok refers to. "-", which refers
+// to the element after the last, is never found.
+func arrayIndex(tok string) (int, bool) {
+	// array-index
+	//     %x30 / ( %x31-39 *(%x30-39) )
+	if tok == "" || len(tok) > 9 || (tok[0] == '0' && len(tok) > 1) {
+		return 0, false
+	}
+	var n int
+	for i := 0; i < len(tok); i++ {
+		if !IsDigit(tok[i]) {
+			return 0, false
+		}
+		n = n*10 + int(tok[i]-'0')
+	}
+	return n, true
+}
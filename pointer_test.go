@@ -0,0 +1,100 @@
+package gojson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// rfc6901 is the example document from section 5 of RFC 6901
+var rfc6901 = []byte(`{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`)
+
+func TestPointer(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		pointer  string
+		expected []byte
+		wantErr  error
+	}{
+		{name: "whole document", input: rfc6901, pointer: ``, expected: rfc6901},
+		{name: "member", input: rfc6901, pointer: `/foo`, expected: []byte(`["bar", "baz"]`)},
+		{name: "element", input: rfc6901, pointer: `/foo/0`, expected: []byte(`"bar"`)},
+		{name: "empty key", input: rfc6901, pointer: `/`, expected: []byte(`0`)},
+		{name: "escaped '/'", input: rfc6901, pointer: `/a~1b`, expected: []byte(`1`)},
+		{name: "percent", input: rfc6901, pointer: `/c%d`, expected: []byte(`2`)},
+		{name: "caret", input: rfc6901, pointer: `/e^f`, expected: []byte(`3`)},
+		{name: "pipe", input: rfc6901, pointer: `/g|h`, expected: []byte(`4`)},
+		{name: "backslash", input: rfc6901, pointer: `/i\j`, expected: []byte(`5`)},
+		{name: "quote", input: rfc6901, pointer: `/k"l`, expected: []byte(`6`)},
+		{name: "space", input: rfc6901, pointer: `/ `, expected: []byte(`7`)},
+		{name: "escaped '~'", input: rfc6901, pointer: `/m~0n`, expected: []byte(`8`)},
+		{name: "nested", input: readFile(t, "example.json"), pointer: `/3/name/first`, expected: []byte(`"Hayden"`)},
+		{name: "~01 is ~1 not /", input: []byte(`{"~1": 1, "/": 2}`), pointer: `/~01`, expected: []byte(`1`)},
+		{name: "missing key", input: rfc6901, pointer: `/nope`, wantErr: ErrNotFound},
+		{name: "index out of range", input: rfc6901, pointer: `/foo/2`, wantErr: ErrNotFound},
+		{name: "past the end", input: rfc6901, pointer: `/foo/-`, wantErr: ErrNotFound},
+		{name: "leading zero", input: rfc6901, pointer: `/foo/01`, wantErr: ErrNotFound},
+		{name: "into a scalar", input: rfc6901, pointer: `/foo/0/0`, wantErr: ErrNotFound},
+		{name: "no leading '/'", input: rfc6901, pointer: `foo`, wantErr: ErrInvalidPointer},
+		{name: "bad escape", input: rfc6901, pointer: `/m~2n`, wantErr: ErrInvalidPointer},
+		{name: "trailing '~'", input: rfc6901, pointer: `/m~`, wantErr: ErrInvalidPointer},
+	}
+
+	for _, tc := range tests {
+		p, err := ParsePointer(tc.pointer)
+		if err == nil && p.String() != tc.pointer {
+			t.Errorf("%s: expected String to return %q: got %q", tc.name, tc.pointer, p.String())
+		}
+
+		var v Value
+		var off int
+		if err == nil {
+			v, off, err = p.Get(tc.input)
+		}
+
+		// the tree should agree with the raw bytes
+		var found *Node
+		if err == nil {
+			var n Node
+			n, _, err = ParseNode(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found, err = p.Find(&n)
+		}
+
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: expected %v: got %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		// byte.Compare returns 0 if equal
+		if bytes.Compare(v, tc.expected) != 0 {
+			t.Errorf("%s: expected %s: got %s", tc.name, tc.expected, v)
+		}
+		if bytes.Compare(tc.input[off:off+len(v)], v) != 0 {
+			t.Errorf("%s: %s is not at offset %d", tc.name, v, off)
+		}
+		if bytes.Compare(found.Raw, v) != 0 || found.Offset != off {
+			t.Errorf("%s: Find returned %s@%d: Get returned %s@%d", tc.name, found.Raw, found.Offset, v, off)
+		}
+	}
+}
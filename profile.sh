@@ -2,7 +2,7 @@
 
 rm -rf /tmp/profile* # todo maybe dont do this
 
-go build -o ./gj cmd/gj/main.go
+go build -o ./gj ./cmd/gj
 profile=`./gj -s 2>&1 | tail -1 | awk '{print $7}'`
 echo profile: ${profile}
 
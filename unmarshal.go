@@ -0,0 +1,625 @@
+package gojson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Unmarshal decodes the json document data into v, which must be a
+// non-nil pointer. It is a drop-in for encoding/json.Unmarshal: json
+// tags, json.Unmarshaler, encoding.TextUnmarshaler, json.Number and
+// json.RawMessage all work the same way, and a value of the wrong type
+// is reported as a *json.UnmarshalTypeError after decoding what it
+// can. The differences are that
+//
+//   - data is checked with ValidateJSON, so a syntax error is a
+//     *SyntaxError
+//   - integers are decoded with Number.Int64, so 1e3 and 2.0 can go in
+//     an int
+//
+// Unmarshal enforces DefaultParseOptions.
+func Unmarshal(data []byte, v interface{}) error {
+	return DefaultParseOptions.Unmarshal(data, v)
+}
+
+// Unmarshal is Unmarshal enforcing o
+func (o ParseOptions) Unmarshal(data []byte, v interface{}) error {
+	if err := o.ValidateJSON(data); err != nil {
+		return err
+	}
+	return unmarshal(data, v)
+}
+
+// Decode decodes the next value into v, see Unmarshal
+func (d *Decoder) Decode(v interface{}) error {
+	val, err := d.Value()
+	if err != nil {
+		return err
+	}
+	return unmarshal(val, v)
+}
+
+// unmarshal is Unmarshal of a valid document
+func unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	_, c := ParseWhitespace(data)
+	val, _, _ := ParseValue(data[c:])
+
+	d := decodeState{}
+	d.value(val, c, rv)
+	return d.err
+}
+
+// decodeState walks a valid document into a reflect.Value. Offsets
+// are from the start of the document.
+type decodeState struct {
+	err error // the first error, decoding carries on past type errors
+
+	// where in the Go value the decoder is, for UnmarshalTypeError
+	structType reflect.Type
+	fieldPath  []string
+}
+
+func (d *decodeState) saveError(err error) {
+	if d.err == nil && err != nil {
+		d.err = err
+	}
+}
+
+// typeError records that the json described by what can't go in a t
+func (d *decodeState) typeError(what string, t reflect.Type, off int) {
+	err := &json.UnmarshalTypeError{Value: what, Type: t, Offset: int64(off)}
+	if d.structType != nil {
+		err.Struct = d.structType.Name()
+		err.Field = strings.Join(d.fieldPath, ".")
+	}
+	d.saveError(err)
+}
+
+func (d *decodeState) value(v Value, off int, rv reflect.Value) {
+	switch v[0] {
+	case '{':
+		d.object(v, off, rv)
+	case '[':
+		d.array(v, off, rv)
+	default:
+		d.literal(v, off, rv)
+	}
+}
+
+// indirect follows pointers down from rv, allocating nil ones, until it
+// reaches something that isn't a pointer or that implements one of the
+// unmarshaler interfaces. Decoding null stops at the last settable
+// pointer so that it can be set to nil.
+//
+// This is encoding/json's indirect, so that the same methods get called.
+func indirect(rv reflect.Value, null bool) (json.Unmarshaler, encoding.TextUnmarshaler, reflect.Value) {
+	// a named type's methods may be on its pointer
+	v0 := rv
+	haveAddr := false
+	if rv.Kind() != reflect.Ptr && rv.Type().Name() != "" && rv.CanAddr() {
+		haveAddr = true
+		rv = rv.Addr()
+	}
+
+	for {
+		// decode into what an interface points to rather than
+		// replacing it
+		if rv.Kind() == reflect.Interface && !rv.IsNil() {
+			e := rv.Elem()
+			if e.Kind() == reflect.Ptr && !e.IsNil() && (!null || e.Elem().Kind() == reflect.Ptr) {
+				haveAddr = false
+				rv = e
+				continue
+			}
+		}
+
+		if rv.Kind() != reflect.Ptr {
+			break
+		}
+		if null && rv.CanSet() {
+			break
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		if rv.Type().NumMethod() > 0 && rv.CanInterface() {
+			if u, ok := rv.Interface().(json.Unmarshaler); ok {
+				return u, nil, reflect.Value{}
+			}
+			if !null {
+				if u, ok := rv.Interface().(encoding.TextUnmarshaler); ok {
+					return nil, u, reflect.Value{}
+				}
+			}
+		}
+
+		if haveAddr {
+			rv = v0 // back to the value Addr was taken of
+			haveAddr = false
+		} else {
+			rv = rv.Elem()
+		}
+	}
+	return nil, nil, rv
+}
+
+// eachMember calls f with each member of the valid object v, and where
+// in v the value starts
+func eachMember(v Value, f func(key String, val Value, c int)) {
+	// object
+	//     '{' ws '}'
+	//     '{' members '}'
+	c := 1
+	for {
+		_, n := ParseWhitespace(v[c:])
+		c += n
+		if v[c] == '}' {
+			return
+		}
+
+		// member
+		//     ws string ws ':' element
+		key, n, _ := ParseString(v[c:])
+		c += n
+		_, n = ParseWhitespace(v[c:])
+		c += n + 1 // the ':'
+		_, n = ParseWhitespace(v[c:])
+		c += n
+
+		val, n, _ := ParseValue(v[c:])
+		f(key, val, c)
+		c += n
+
+		_, n = ParseWhitespace(v[c:])
+		c += n
+		if v[c] == '}' {
+			return
+		}
+		c++ // consume the ','
+	}
+}
+
+// eachElement calls f with each element of the valid array v, and
+// where in v it starts
+func eachElement(v Value, f func(e Value, c int)) {
+	c := 1
+	for {
+		_, n := ParseWhitespace(v[c:])
+		c += n
+		if v[c] == ']' {
+			return
+		}
+
+		e, n, _ := ParseValue(v[c:])
+		f(e, c)
+		c += n
+
+		_, n = ParseWhitespace(v[c:])
+		c += n
+		if v[c] == ']' {
+			return
+		}
+		c++ // consume the ','
+	}
+}
+
+func (d *decodeState) object(v Value, off int, rv reflect.Value) {
+	u, ut, pv := indirect(rv, false)
+	if u != nil {
+		d.saveError(u.UnmarshalJSON(v))
+		return
+	}
+	if ut != nil {
+		d.typeError("object", rv.Type(), off)
+		return
+	}
+	rv = pv
+
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		m := map[string]interface{}{}
+		eachMember(v, func(key String, val Value, c int) {
+			var e interface{}
+			d.value(val, off+c, reflect.ValueOf(&e).Elem())
+			m[unquote(key)] = e
+		})
+		rv.Set(reflect.ValueOf(m))
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		d.mapMembers(v, off, rv)
+	case reflect.Struct:
+		d.structMembers(v, off, rv)
+	default:
+		d.typeError("object", rv.Type(), off)
+	}
+}
+
+func (d *decodeState) mapMembers(v Value, off int, rv reflect.Value) {
+	t := rv.Type()
+	kt := t.Key()
+	switch kt.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		if !reflect.PtrTo(kt).Implements(textUnmarshalerType) {
+			d.typeError("object", t, off)
+			return
+		}
+	}
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMap(t))
+	}
+
+	eachMember(v, func(key String, val Value, c int) {
+		elem := reflect.New(t.Elem()).Elem()
+		d.value(val, off+c, elem)
+
+		s := unquote(key)
+		var kv reflect.Value
+		switch {
+		case reflect.PtrTo(kt).Implements(textUnmarshalerType):
+			kv = reflect.New(kt)
+			if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+				d.saveError(err)
+				return
+			}
+			kv = kv.Elem()
+		case kt.Kind() == reflect.String:
+			kv = reflect.ValueOf(s).Convert(kt)
+		default:
+			kv = reflect.New(kt).Elem()
+			if !setInteger(kv, Number(s)) {
+				d.typeError("number "+s, kt, off+c)
+				return
+			}
+		}
+		rv.SetMapIndex(kv, elem)
+	})
+}
+
+func (d *decodeState) structMembers(v Value, off int, rv reflect.Value) {
+	fields := cachedFields(rv.Type())
+
+	outerType, outerPath := d.structType, d.fieldPath
+	defer func() { d.structType, d.fieldPath = outerType, outerPath }()
+	d.structType = rv.Type()
+
+	eachMember(v, func(key String, val Value, c int) {
+		name, _ := key.Unquote()
+		f := fields.find(name)
+		if f == nil {
+			return
+		}
+
+		// allocate the embedded structs on the way to the field
+		sub := rv
+		for _, i := range f.index {
+			if sub.Kind() == reflect.Ptr {
+				if sub.IsNil() {
+					if !sub.CanSet() {
+						d.saveError(fmt.Errorf("json: cannot set embedded pointer to unexported struct: %v", sub.Type().Elem()))
+						return
+					}
+					sub.Set(reflect.New(sub.Type().Elem()))
+				}
+				sub = sub.Elem()
+			}
+			sub = sub.Field(i)
+		}
+
+		d.fieldPath = append(outerPath[:len(outerPath):len(outerPath)], f.name)
+		if f.quoted {
+			d.quoted(val, off+c, sub)
+			return
+		}
+		d.value(val, off+c, sub)
+	})
+}
+
+// quoted decodes the value of a field tagged ",string", a string
+// holding a literal
+func (d *decodeState) quoted(v Value, off int, rv reflect.Value) {
+	if v[0] == 'n' {
+		d.literal(v, off, rv)
+		return
+	}
+
+	var inner []byte
+	if v[0] == '"' {
+		inner, _ = String(v).Unquote()
+	}
+	lit, c, err := ParseValue(inner)
+	if v[0] != '"' || err != nil || c != len(inner) || lit[0] == '{' || lit[0] == '[' ||
+		(lit[0] == '"') != (rv.Kind() == reflect.String) {
+		d.saveError(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %s into %v", v, rv.Type()))
+		return
+	}
+	d.literal(lit, off, rv)
+}
+
+func (d *decodeState) array(v Value, off int, rv reflect.Value) {
+	u, ut, pv := indirect(rv, false)
+	if u != nil {
+		d.saveError(u.UnmarshalJSON(v))
+		return
+	}
+	if ut != nil {
+		d.typeError("array", rv.Type(), off)
+		return
+	}
+	rv = pv
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			d.typeError("array", rv.Type(), off)
+			return
+		}
+		a := []interface{}{}
+		eachElement(v, func(e Value, c int) {
+			var x interface{}
+			d.value(e, off+c, reflect.ValueOf(&x).Elem())
+			a = append(a, x)
+		})
+		rv.Set(reflect.ValueOf(a))
+		return
+	case reflect.Array, reflect.Slice:
+	default:
+		d.typeError("array", rv.Type(), off)
+		return
+	}
+
+	// elements already in a slice are decoded into, like encoding/json
+	i := 0
+	eachElement(v, func(e Value, c int) {
+		if rv.Kind() == reflect.Slice && i >= rv.Len() {
+			if i < rv.Cap() {
+				rv.SetLen(i + 1)
+			} else {
+				rv.Set(reflect.Append(rv, reflect.Zero(rv.Type().Elem())))
+			}
+		}
+		if i < rv.Len() {
+			d.value(e, off+c, rv.Index(i))
+		}
+		i++
+	})
+
+	switch {
+	case rv.Kind() == reflect.Array:
+		for ; i < rv.Len(); i++ {
+			rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+		}
+	case i == 0:
+		rv.Set(reflect.MakeSlice(rv.Type(), 0, 0))
+	case i < rv.Len():
+		rv.SetLen(i)
+	}
+}
+
+// literal decodes a string, number, boolean or null
+func (d *decodeState) literal(v Value, off int, rv reflect.Value) {
+	null := v[0] == 'n'
+	u, ut, pv := indirect(rv, null)
+	if u != nil {
+		d.saveError(u.UnmarshalJSON(v))
+		return
+	}
+	if ut != nil {
+		if v[0] != '"' {
+			d.typeError(literalName(v), rv.Type(), off)
+			return
+		}
+		s, _ := String(v).Unquote()
+		d.saveError(ut.UnmarshalText(s))
+		return
+	}
+	rv = pv
+
+	switch v[0] {
+	case 'n':
+		// null only does something to things that can be nil
+		switch rv.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+
+	case 't', 'f':
+		b := v[0] == 't'
+		switch {
+		case rv.Kind() == reflect.Bool:
+			rv.SetBool(b)
+		case rv.Kind() == reflect.Interface && rv.NumMethod() == 0:
+			rv.Set(reflect.ValueOf(b))
+		default:
+			d.typeError("bool", rv.Type(), off)
+		}
+
+	case '"':
+		s, _ := String(v).Unquote()
+		switch {
+		case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+			b := make([]byte, base64.StdEncoding.DecodedLen(len(s)))
+			n, err := base64.StdEncoding.Decode(b, s)
+			if err != nil {
+				d.saveError(err)
+				return
+			}
+			rv.SetBytes(b[:n])
+		case rv.Type() == jsonNumberType:
+			if len(s) > 0 && !Number(s).valid() {
+				d.saveError(fmt.Errorf("json: invalid number literal, trying to unmarshal %s into Number", v))
+				return
+			}
+			rv.SetString(string(s))
+		case rv.Kind() == reflect.String:
+			rv.SetString(string(s))
+		case rv.Kind() == reflect.Interface && rv.NumMethod() == 0:
+			rv.Set(reflect.ValueOf(string(s)))
+		default:
+			d.typeError("string", rv.Type(), off)
+		}
+
+	default:
+		n := Number(v)
+		switch rv.Kind() {
+		case reflect.Interface:
+			if rv.NumMethod() != 0 {
+				d.typeError("number", rv.Type(), off)
+				return
+			}
+			f, err := n.Float64()
+			if err != nil {
+				d.typeError("number "+string(n), rv.Type(), off)
+				return
+			}
+			rv.Set(reflect.ValueOf(f))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if !setInteger(rv, n) {
+				d.typeError("number "+string(n), rv.Type(), off)
+			}
+		case reflect.Float32, reflect.Float64:
+			f, err := n.Float64()
+			if err != nil || rv.OverflowFloat(f) {
+				d.typeError("number "+string(n), rv.Type(), off)
+				return
+			}
+			rv.SetFloat(f)
+		case reflect.String:
+			if rv.Type() != jsonNumberType {
+				d.typeError("number", rv.Type(), off)
+				return
+			}
+			rv.SetString(string(n))
+		default:
+			d.typeError("number", rv.Type(), off)
+		}
+	}
+}
+
+// setInteger sets the int or uint rv to n, reporting whether n fit
+func setInteger(rv reflect.Value, n Number) bool {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := n.Int64()
+		if err != nil || rv.OverflowInt(i) {
+			return false
+		}
+		rv.SetInt(i)
+	default:
+		u, err := n.Uint64()
+		if err != nil || rv.OverflowUint(u) {
+			return false
+		}
+		rv.SetUint(u)
+	}
+	return true
+}
+
+// GenState is what the decoders gj gen generates carry through a
+// document, so that they report errors the way Unmarshal does: the
+// first error is kept and decoding carries on with the rest.
+type GenState struct {
+	Err error
+
+	// Struct is the name of the struct being decoded and Field the json
+	// names of the fields leading to the value being decoded, for
+	// UnmarshalTypeError
+	Struct string
+	Field  []string
+}
+
+// SaveError keeps err if it is the first error
+func (s *GenState) SaveError(err error) {
+	if s.Err == nil && err != nil {
+		s.Err = err
+	}
+}
+
+// TypeError records that the value at data[c] can't go in the Go value
+// v points to
+func (s *GenState) TypeError(data []byte, c int, v interface{}) {
+	s.typeError(literalName(data[c:]), c, v)
+}
+
+// NumberError records that the number at data[c] doesn't fit in the Go
+// value v points to
+func (s *GenState) NumberError(data []byte, c int, v interface{}) {
+	n, _, _ := ParseNumber(data[c:])
+	s.typeError("number "+string(n), c, v)
+}
+
+func (s *GenState) typeError(what string, c int, v interface{}) {
+	err := &json.UnmarshalTypeError{Value: what, Type: reflect.TypeOf(v).Elem(), Offset: int64(c)}
+	if s.Struct != "" {
+		err.Struct = s.Struct
+		err.Field = strings.Join(s.Field, ".")
+	}
+	s.SaveError(err)
+}
+
+// QuotedError records that the value at data[c] isn't a string holding
+// a literal that can go in the Go value v points to, which is tagged
+// ",string"
+func (s *GenState) QuotedError(data []byte, c int, v interface{}) {
+	val, _, _ := ParseValue(data[c:])
+	s.SaveError(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %s into %v", val, reflect.TypeOf(v).Elem()))
+}
+
+// Unmarshal decodes the value at data[c] into v with Unmarshal, for
+// the types the generated code leaves to reflection, returning where
+// the value ends
+func (s *GenState) Unmarshal(data []byte, c int, v interface{}) int {
+	val, n, _ := ParseValue(data[c:])
+
+	// the fields of structs inside v carry on from s.Field
+	d := decodeState{fieldPath: s.Field}
+	d.value(val, c, reflect.ValueOf(v))
+	if e, ok := d.err.(*json.UnmarshalTypeError); ok && e.Struct == "" && s.Struct != "" {
+		e.Struct = s.Struct
+		e.Field = strings.Join(s.Field, ".")
+	}
+	s.SaveError(d.err)
+	return c + n
+}
+
+// literalName describes a value for an UnmarshalTypeError
+func literalName(v Value) string {
+	switch v[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 'n':
+		return "null"
+	case 't', 'f':
+		return "bool"
+	case '"':
+		return "string"
+	}
+	return "number"
+}
+
+// valid reports whether n is exactly one json number
+func (n Number) valid() bool {
+	_, _, _, err := n.parts()
+	return err == nil
+}
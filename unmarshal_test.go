@@ -0,0 +1,180 @@
+package gojson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type person struct {
+	ID      string   `json:"_id"`
+	Age     int      `json:"age"`
+	Active  bool     `json:"isActive"`
+	Balance string   `json:"balance,omitempty"`
+	Tags    []string `json:"tags"`
+	Name    struct {
+		First string `json:"first"`
+		Last  string
+	} `json:"name"`
+	Friends []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"friends"`
+	Latitude float64 `json:"latitude,string"`
+	Ignored  string  `json:"-"`
+	private  string
+}
+
+type Embedded struct {
+	A int
+	B int `json:"b"`
+}
+
+type withEmbedded struct {
+	Embedded
+	*Inner
+	B string // deeper fields lose to shallower ones
+}
+
+type Inner struct {
+	C []int
+}
+
+// upper decodes strings in upper case, through its pointer
+type upper string
+
+func (u *upper) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*u = upper(strings.ToUpper(s))
+	return nil
+}
+
+type quoted struct {
+	I int     `json:",string"`
+	F float64 `json:",string"`
+	B bool    `json:",string"`
+	S string  `json:",string"`
+	P *int    `json:",string"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	example := readFile(t, "example.json")
+
+	tests := []struct {
+		name  string
+		input string
+		// new returns a pointer to decode into, each decoder gets its own
+		new func() interface{}
+	}{
+		{name: "example", input: string(example), new: func() interface{} { return &[]person{} }},
+		{name: "example as interface", input: string(example), new: func() interface{} { return new(interface{}) }},
+		{name: "example as maps", input: string(example), new: func() interface{} { return &[]map[string]json.RawMessage{} }},
+		{name: "primitives", input: `[1, -2.5, "x", true, null]`, new: func() interface{} { return &[]interface{}{} }},
+		{name: "ints", input: `[0, -128, 127]`, new: func() interface{} { return &[]int8{} }},
+		{name: "fixed array", input: `[1, 2, 3]`, new: func() interface{} { return &[2]int{} }},
+		{name: "short fixed array", input: `[1]`, new: func() interface{} { return &[2]int{7, 7} }},
+		{name: "empty slice", input: `[]`, new: func() interface{} { return &[]int{1} }},
+		{name: "slice decodes into what is there", input: `[{"b": 1}]`, new: func() interface{} { return &[]Embedded{{A: 1, B: 2}, {A: 3}} }},
+		{name: "pointers", input: `{"A": 1, "b": null}`, new: func() interface{} { return &map[string]*int{} }},
+		{name: "null pointer", input: `null`, new: func() interface{} { i := 1; p := &i; return &p }},
+		{name: "null leaves an int", input: `null`, new: func() interface{} { i := 1; return &i }},
+		{name: "int keys", input: `{"1": "a", "-2": "b"}`, new: func() interface{} { return &map[int]string{} }},
+		{name: "text unmarshaler", input: `"2021-02-03T04:05:06Z"`, new: func() interface{} { return &time.Time{} }},
+		{name: "unmarshaler", input: `["a", "b"]`, new: func() interface{} { return &[]upper{} }},
+		{name: "bytes are base64", input: `"aGVsbG8="`, new: func() interface{} { return &[]byte{} }},
+		{name: "number", input: `[1.50, "2"]`, new: func() interface{} { return &[]json.Number{} }},
+		{name: "escapes", input: `{"A": "😀\n"}`, new: func() interface{} { return &Embedded{} }},
+		{name: "escaped string", input: `"😀\n\""`, new: func() interface{} { return new(string) }},
+		{name: "case insensitive", input: `{"a": 1, "B": 2}`, new: func() interface{} { return &Embedded{} }},
+		{name: "embedded", input: `{"A": 1, "b": "x", "C": [1]}`, new: func() interface{} { return &withEmbedded{} }},
+		{name: "quoted", input: `{"I": "1", "F": "2.5", "B": "true", "S": "\"s\"", "P": "3"}`, new: func() interface{} { return &quoted{} }},
+		{name: "quoted null", input: `{"P": null}`, new: func() interface{} { return &quoted{} }},
+		{name: "interface holding a pointer", input: `{"b": 5}`, new: func() interface{} { var i interface{} = &Embedded{A: 1}; return &i }},
+		{name: "wrong type carries on", input: `{"A": "x", "b": 2}`, new: func() interface{} { return &Embedded{} }},
+		{name: "wrong type in a slice", input: `[1, "x", 3]`, new: func() interface{} { return &[]int{} }},
+		{name: "overflow", input: `[300]`, new: func() interface{} { return &[]int8{} }},
+		{name: "negative uint", input: `-1`, new: func() interface{} { return new(uint) }},
+		{name: "fraction in an int", input: `1.5`, new: func() interface{} { return new(int) }},
+	}
+
+	for _, tc := range tests {
+		expected := tc.new()
+		expectedErr := json.Unmarshal([]byte(tc.input), expected)
+
+		actual := tc.new()
+		err := Unmarshal([]byte(tc.input), actual)
+
+		if (err == nil) != (expectedErr == nil) {
+			t.Errorf("%s: expected error %v: got %v", tc.name, expectedErr, err)
+			continue
+		}
+		var expectedType, actualType *json.UnmarshalTypeError
+		if errors.As(expectedErr, &expectedType) {
+			if !errors.As(err, &actualType) {
+				t.Errorf("%s: expected a *json.UnmarshalTypeError: got %v", tc.name, err)
+			} else if actualType.Type != expectedType.Type {
+				t.Errorf("%s: expected %v: got %v", tc.name, expectedType, actualType)
+			}
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %#v: got %#v", tc.name, expected, actual)
+		}
+	}
+}
+
+func TestUnmarshalDifferences(t *testing.T) {
+	// integers are decoded with Number.Int64
+	var i []int64
+	if err := Unmarshal([]byte(`[1e3, 2.0, 9223372036854775807]`), &i); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int64{1000, 2, 1<<63 - 1}; !reflect.DeepEqual(i, expected) {
+		t.Errorf("expected %v: got %v", expected, i)
+	}
+
+	var se *SyntaxError
+	if err := Unmarshal([]byte(`{"a": 1,}`), &i); !errors.As(err, &se) || se.Offset != 8 {
+		t.Errorf("expected a *SyntaxError at offset 8: got %v", err)
+	}
+
+	var iue *json.InvalidUnmarshalError
+	if err := Unmarshal([]byte(`1`), i); !errors.As(err, &iue) {
+		t.Errorf("expected a *json.InvalidUnmarshalError: got %v", err)
+	}
+
+	if err := Unmarshal([]byte(`{"I": 1}`), &quoted{}); err == nil {
+		t.Errorf("expected ,string to need a string")
+	}
+
+	opts := ParseOptions{MaxDepth: 2}
+	if err := opts.Unmarshal([]byte(`[[[1]]]`), new(interface{})); !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Errorf("expected %v: got %v", ErrMaxDepthExceeded, err)
+	}
+}
+
+func TestDecoderDecode(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"A": 1} {"A": 2}`))
+
+	var actual []int
+	for {
+		var e Embedded
+		err := d.Decode(&e)
+		if err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+		actual = append(actual, e.A)
+	}
+	if !reflect.DeepEqual(actual, []int{1, 2}) {
+		t.Errorf("expected [1 2]: got %v", actual)
+	}
+}
@@ -0,0 +1,126 @@
+package gojson
+
+// Kind returns the Kind of v from its first byte, without checking v is
+// valid. It returns KindInvalid if v can't be the start of a value.
+func (v Value) Kind() Kind {
+	if len(v) == 0 {
+		return KindInvalid
+	}
+
+	switch v[0] {
+	case '{':
+		return KindObject
+	case '[':
+		return KindArray
+	case '"':
+		return KindString
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return KindNumber
+	case 't', 'f':
+		return KindBool
+	case 'n':
+		return KindNull
+	}
+	return KindInvalid
+}
+
+func (v Value) IsObject() bool { return v.Kind() == KindObject }
+func (v Value) IsArray() bool  { return v.Kind() == KindArray }
+func (v Value) IsString() bool { return v.Kind() == KindString }
+func (v Value) IsNumber() bool { return v.Kind() == KindNumber }
+func (v Value) IsBool() bool   { return v.Kind() == KindBool }
+func (v Value) IsNull() bool   { return v.Kind() == KindNull }
+
+// The As functions check the whole of v is a value of their type and
+// return it as that type. A value of the wrong type fails the same way
+// its Parse function does, e.g. AsObject of "[]" is an
+// ErrInvalidObjectOpen.
+
+func (v Value) AsObject() (Object, error) {
+	_, c, err := ParseObject(v)
+	if err = whole(v, c, err); err != nil {
+		return nil, err
+	}
+	return Object(v), nil
+}
+
+// AsArray returns the elements of the array v
+func (v Value) AsArray() ([]Value, error) {
+	_, c, err := ParseArray(v)
+	if err = whole(v, c, err); err != nil {
+		return nil, err
+	}
+
+	// array
+	//     '[' ws ']'
+	//     '[' elements ']'
+	//
+	// v is known to be valid so only the separators need skipping
+	values := []Value{}
+	c = 1
+	for {
+		_, n := ParseWhitespace(v[c:])
+		c += n
+		if v[c] == ']' {
+			return values, nil
+		}
+
+		e, n, _ := ParseValue(v[c:])
+		values = append(values, e)
+		c += n
+
+		_, n = ParseWhitespace(v[c:])
+		c += n
+		if v[c] == ']' {
+			return values, nil
+		}
+		c++ // consume the ','
+	}
+}
+
+func (v Value) AsString() (String, error) {
+	_, c, err := ParseString(v)
+	if err = whole(v, c, err); err != nil {
+		return nil, err
+	}
+	return String(v), nil
+}
+
+func (v Value) AsNumber() (Number, error) {
+	_, c, err := ParseNumber(v)
+	if err == nil {
+		err = checkNumberEnd(v, c)
+	}
+	if err = whole(v, c, err); err != nil {
+		return nil, err
+	}
+	return Number(v), nil
+}
+
+func (v Value) AsBoolean() (Boolean, error) {
+	_, c, err := ParseBoolean(v)
+	if err = whole(v, c, err); err != nil {
+		return nil, err
+	}
+	return Boolean(v), nil
+}
+
+func (v Value) AsNull() (Null, error) {
+	_, c, err := ParseNull(v)
+	if err = whole(v, c, err); err != nil {
+		return nil, err
+	}
+	return Null(v), nil
+}
+
+// whole returns err, or ErrTrailingData if parsing v stopped short of
+// the end of it
+func whole(v Value, c int, err error) error {
+	if err != nil {
+		return err
+	}
+	if c != len(v) {
+		return syntaxError(v, c, "end of input", ErrTrailingData)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package gojson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestValueKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Value
+		expected Kind
+	}{
+		{name: "object", input: Value(`{"a":1}`), expected: KindObject},
+		{name: "array", input: Value(`[1]`), expected: KindArray},
+		{name: "string", input: Value(`"a"`), expected: KindString},
+		{name: "number", input: Value(`-1`), expected: KindNumber},
+		{name: "zero", input: Value(`0`), expected: KindNumber},
+		{name: "true", input: Value(`true`), expected: KindBool},
+		{name: "false", input: Value(`false`), expected: KindBool},
+		{name: "null", input: Value(`null`), expected: KindNull},
+		{name: "empty", input: Value(``), expected: KindInvalid},
+		{name: "leading whitespace", input: Value(` 1`), expected: KindInvalid},
+		{name: "only the first byte is looked at", input: Value(`{nope`), expected: KindObject},
+	}
+
+	for _, tc := range tests {
+		if k := tc.input.Kind(); k != tc.expected {
+			t.Errorf("%s: expected %s: got %s", tc.name, tc.expected, k)
+		}
+
+		is := map[Kind]bool{
+			KindObject: tc.input.IsObject(),
+			KindArray:  tc.input.IsArray(),
+			KindString: tc.input.IsString(),
+			KindNumber: tc.input.IsNumber(),
+			KindBool:   tc.input.IsBool(),
+			KindNull:   tc.input.IsNull(),
+		}
+		for k, actual := range is {
+			if actual != (k == tc.expected) {
+				t.Errorf("%s: Is%s returned %v", tc.name, k, actual)
+			}
+		}
+	}
+}
+
+func TestValueAs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Value
+		as       func(Value) (interface{}, error)
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "object",
+			input:    Value(`{"a": 1}`),
+			as:       func(v Value) (interface{}, error) { return v.AsObject() },
+			expected: `{"a": 1}`,
+		},
+		{
+			name:    "object from an array",
+			input:   Value(`[1]`),
+			as:      func(v Value) (interface{}, error) { return v.AsObject() },
+			wantErr: ErrInvalidObjectOpen,
+		},
+		{
+			name:     "array",
+			input:    Value(`[ 1, "a" ,{"b": [2]}, [] ]`),
+			as:       func(v Value) (interface{}, error) { return v.AsArray() },
+			expected: `[1 "a" {"b": [2]} []]`,
+		},
+		{
+			name:     "empty array",
+			input:    Value(`[ ]`),
+			as:       func(v Value) (interface{}, error) { return v.AsArray() },
+			expected: `[]`,
+		},
+		{
+			name:    "array with trailing data",
+			input:   Value(`[1] 2`),
+			as:      func(v Value) (interface{}, error) { return v.AsArray() },
+			wantErr: ErrTrailingData,
+		},
+		{
+			name:     "string",
+			input:    Value(`"a\nb"`),
+			as:       func(v Value) (interface{}, error) { return v.AsString() },
+			expected: `"a\nb"`,
+		},
+		{
+			name:    "string from a number",
+			input:   Value(`1`),
+			as:      func(v Value) (interface{}, error) { return v.AsString() },
+			wantErr: ErrInvalidStringOpen,
+		},
+		{
+			name:     "number",
+			input:    Value(`-1.5e3`),
+			as:       func(v Value) (interface{}, error) { return v.AsNumber() },
+			expected: `-1.5e3`,
+		},
+		{
+			name:    "malformed number",
+			input:   Value(`01`),
+			as:      func(v Value) (interface{}, error) { return v.AsNumber() },
+			wantErr: ErrInvalidNumber,
+		},
+		{
+			name:     "boolean",
+			input:    Value(`false`),
+			as:       func(v Value) (interface{}, error) { return v.AsBoolean() },
+			expected: `false`,
+		},
+		{
+			name:     "null",
+			input:    Value(`null`),
+			as:       func(v Value) (interface{}, error) { return v.AsNull() },
+			expected: `null`,
+		},
+		{
+			name:    "null with trailing data",
+			input:   Value(`nullnull`),
+			as:      func(v Value) (interface{}, error) { return v.AsNull() },
+			wantErr: ErrTrailingData,
+		},
+	}
+
+	for _, tc := range tests {
+		actual, err := tc.as(tc.input)
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: expected %v: got %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		// %s would call String.String
+		if s := fmt.Sprintf("%s", raw(actual)); s != tc.expected {
+			t.Errorf("%s: expected %s: got %s", tc.name, tc.expected, s)
+		}
+	}
+}
+
+// raw turns the typed values returned by the As functions back into
+// plain bytes for printing
+func raw(v interface{}) interface{} {
+	switch v := v.(type) {
+	case String:
+		return []byte(v)
+	case []Value:
+		b := make([][]byte, len(v))
+		for i := range v {
+			b[i] = v[i]
+		}
+		return b
+	}
+	return v
+}
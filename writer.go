@@ -0,0 +1,266 @@
+package gojson
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+var (
+	// ErrWriterState is returned when a Writer method is called out of
+	// turn, e.g. a value in an object before its Key or EndArray closing
+	// an object. Nothing is written and the Writer can carry on.
+	ErrWriterState = fmt.Errorf("writer: call is not valid here")
+
+	// ErrUnsupportedFloat is returned for NaN and ±Inf, which json has
+	// no way of writing
+	ErrUnsupportedFloat = fmt.Errorf("writer: unsupported float")
+)
+
+// flushSize is how much a Writer buffers before writing to its
+// io.Writer
+const flushSize = 4096
+
+// Writer builds a json document one piece of the grammar at a time,
+// the reverse of a Decoder, e.g.
+//
+//	w := NewWriter(out)
+//	w.BeginObject()
+//	w.Key("name")
+//	w.String(name)
+//	w.Key("tags")
+//	w.BeginArray()
+//	for _, t := range tags {
+//		w.String(t)
+//	}
+//	w.EndArray()
+//	w.EndObject()
+//	err := w.Close()
+//
+// Commas and colons are written for you and strings are escaped, so
+// the output is valid json as long as no method returns an error.
+// Calls out of turn return ErrWriterState. An error from the io.Writer
+// is returned by every call after it.
+type Writer struct {
+	w   io.Writer // nil when only appending to buf
+	buf []byte
+	err error // from w
+
+	stack []container
+	done  bool // the top-level value is complete
+}
+
+// NewWriter returns a Writer writing to w. Output is buffered until
+// the document is complete or Flush is called.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// NewAppendWriter returns a Writer appending to dst, see Bytes
+func NewAppendWriter(dst []byte) *Writer {
+	return &Writer{buf: dst}
+}
+
+// Bytes returns dst with what has been written appended to it, for a
+// Writer from NewAppendWriter. For one from NewWriter it returns what
+// is yet to be flushed.
+func (w *Writer) Bytes() []byte {
+	return w.buf
+}
+
+// Flush writes anything buffered to the io.Writer
+func (w *Writer) Flush() error {
+	if w.err != nil || w.w == nil || len(w.buf) == 0 {
+		return w.err
+	}
+	_, w.err = w.w.Write(w.buf)
+	w.buf = w.buf[:0]
+	return w.err
+}
+
+// Close flushes the Writer and returns ErrWriterState if the document
+// is incomplete
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !w.done {
+		return ErrWriterState
+	}
+	return nil
+}
+
+func (w *Writer) BeginObject() error {
+	return w.begin('{')
+}
+
+func (w *Writer) EndObject() error {
+	return w.end('{', '}')
+}
+
+func (w *Writer) BeginArray() error {
+	return w.begin('[')
+}
+
+func (w *Writer) EndArray() error {
+	return w.end('[', ']')
+}
+
+// Key writes the key of the next member of the current object
+func (w *Writer) Key(k string) error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.stack) == 0 {
+		return ErrWriterState
+	}
+	top := &w.stack[len(w.stack)-1]
+	if top.open != '{' || top.state == stateKey {
+		return ErrWriterState
+	}
+
+	if top.n > 0 {
+		w.buf = append(w.buf, ',')
+	}
+	w.buf = appendString(w.buf, k, false)
+	w.buf = append(w.buf, ':')
+	top.state = stateKey
+	return nil
+}
+
+func (w *Writer) String(s string) error {
+	if err := w.value(); err != nil {
+		return err
+	}
+	w.buf = appendString(w.buf, s, false)
+	return w.wrote()
+}
+
+func (w *Writer) Int(i int64) error {
+	if err := w.value(); err != nil {
+		return err
+	}
+	w.buf = strconv.AppendInt(w.buf, i, 10)
+	return w.wrote()
+}
+
+func (w *Writer) Uint(u uint64) error {
+	if err := w.value(); err != nil {
+		return err
+	}
+	w.buf = strconv.AppendUint(w.buf, u, 10)
+	return w.wrote()
+}
+
+// Float writes f as Marshal would. NaN and ±Inf have no json number
+// and return ErrUnsupportedFloat.
+func (w *Writer) Float(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("%w: %v", ErrUnsupportedFloat, f)
+	}
+	if err := w.value(); err != nil {
+		return err
+	}
+	w.buf = appendFloat(w.buf, f, 64)
+	return w.wrote()
+}
+
+func (w *Writer) Bool(b bool) error {
+	if err := w.value(); err != nil {
+		return err
+	}
+	w.buf = strconv.AppendBool(w.buf, b)
+	return w.wrote()
+}
+
+func (w *Writer) Null() error {
+	if err := w.value(); err != nil {
+		return err
+	}
+	w.buf = append(w.buf, "null"...)
+	return w.wrote()
+}
+
+// Raw writes the json value b as it is, for values encoded elsewhere.
+// b must be a single valid value, surrounding whitespace is fine.
+func (w *Writer) Raw(b []byte) error {
+	if err := ValidateJSON(b); err != nil {
+		return err
+	}
+	if err := w.value(); err != nil {
+		return err
+	}
+	_, c := ParseWhitespace(b)
+	w.buf = append(w.buf, bytes.TrimRight(b[c:], " \t\r\n")...)
+	return w.wrote()
+}
+
+// value checks a value can be written here and writes the ',' before
+// it if there needs to be one
+func (w *Writer) value() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.stack) == 0 {
+		if w.done {
+			return ErrWriterState
+		}
+		return nil
+	}
+
+	top := &w.stack[len(w.stack)-1]
+	if top.open == '{' {
+		if top.state != stateKey {
+			return ErrWriterState
+		}
+		return nil
+	}
+	if top.n > 0 {
+		w.buf = append(w.buf, ',')
+	}
+	return nil
+}
+
+// wrote records that a value has been written, flushing once a
+// top-level value is complete or the buffer is full
+func (w *Writer) wrote() error {
+	if len(w.stack) == 0 {
+		w.done = true
+		return w.Flush()
+	}
+	top := &w.stack[len(w.stack)-1]
+	top.state = stateValue
+	top.n++
+	if len(w.buf) >= flushSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+func (w *Writer) begin(open byte) error {
+	if err := w.value(); err != nil {
+		return err
+	}
+	w.buf = append(w.buf, open)
+	w.stack = append(w.stack, container{open: open})
+	return nil
+}
+
+func (w *Writer) end(open, close byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.stack) == 0 {
+		return ErrWriterState
+	}
+	top := w.stack[len(w.stack)-1]
+	if top.open != open || top.state == stateKey {
+		return ErrWriterState
+	}
+
+	w.buf = append(w.buf, close)
+	w.stack = w.stack[:len(w.stack)-1]
+	return w.wrote()
+}
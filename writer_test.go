@@ -0,0 +1,276 @@
+package gojson
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// firstErr returns the first of errs that isn't nil
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(w *Writer) error
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "literals",
+			write:    func(w *Writer) error { return w.Int(-12) },
+			expected: `-12`,
+		},
+		{
+			name: "object",
+			write: func(w *Writer) error {
+				return firstErr(
+					w.BeginObject(),
+					w.Key("s"), w.String("a\"b\n<"),
+					w.Key("i"), w.Int(math.MinInt64),
+					w.Key("u"), w.Uint(math.MaxUint64),
+					w.Key("f"), w.Float(1e21),
+					w.Key("t"), w.Bool(true),
+					w.Key("n"), w.Null(),
+					w.Key("r"), w.Raw([]byte(" [1, {\"x\": 2}]\n")),
+					w.Key("o"), w.BeginObject(), w.EndObject(),
+					w.EndObject(),
+				)
+			},
+			expected: `{"s":"a\"b\n<","i":-9223372036854775808,"u":18446744073709551615,"f":1e+21,"t":true,"n":null,"r":[1, {"x": 2}],"o":{}}`,
+		},
+		{
+			name: "array",
+			write: func(w *Writer) error {
+				return firstErr(
+					w.BeginArray(),
+					w.BeginArray(), w.EndArray(),
+					w.Float(0.5), w.String(""),
+					w.BeginArray(), w.Int(1), w.Int(2), w.EndArray(),
+					w.EndArray(),
+				)
+			},
+			expected: `[[],0.5,"",[1,2]]`,
+		},
+		{
+			name: "value without key",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginObject(), w.String("a"))
+			},
+			expected: `{`,
+			wantErr:  ErrWriterState,
+		},
+		{
+			name: "key after key",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginObject(), w.Key("a"), w.Key("b"))
+			},
+			expected: `{"a":`,
+			wantErr:  ErrWriterState,
+		},
+		{
+			name: "key in array",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginArray(), w.Key("a"))
+			},
+			expected: `[`,
+			wantErr:  ErrWriterState,
+		},
+		{
+			name:    "key at top level",
+			write:   func(w *Writer) error { return w.Key("a") },
+			wantErr: ErrWriterState,
+		},
+		{
+			name: "end object without value",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginObject(), w.Key("a"), w.EndObject())
+			},
+			expected: `{"a":`,
+			wantErr:  ErrWriterState,
+		},
+		{
+			name: "mismatched end",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginArray(), w.EndObject())
+			},
+			expected: `[`,
+			wantErr:  ErrWriterState,
+		},
+		{
+			name:    "end at top level",
+			write:   func(w *Writer) error { return w.EndArray() },
+			wantErr: ErrWriterState,
+		},
+		{
+			name: "second top-level value",
+			write: func(w *Writer) error {
+				return firstErr(w.Null(), w.Null())
+			},
+			expected: `null`,
+			wantErr:  ErrWriterState,
+		},
+		{
+			name: "NaN",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginArray(), w.Float(math.NaN()))
+			},
+			expected: `[`,
+			wantErr:  ErrUnsupportedFloat,
+		},
+		{
+			name: "invalid raw",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginArray(), w.Raw([]byte(`[1,]`)))
+			},
+			expected: `[`,
+			wantErr:  ErrUnsupported,
+		},
+		{
+			name: "raw with trailing data",
+			write: func(w *Writer) error {
+				return firstErr(w.BeginArray(), w.Raw([]byte(`1 2`)))
+			},
+			expected: `[`,
+			wantErr:  ErrTrailingData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewAppendWriter([]byte("prefix:"))
+			err := tt.write(w)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual := string(w.Bytes()); actual != "prefix:"+tt.expected {
+				t.Errorf("expected %s, got %s", "prefix:"+tt.expected, actual)
+			}
+
+			var buf bytes.Buffer
+			w = NewWriter(&buf)
+			tt.write(w)
+			w.Flush()
+			if buf.String() != tt.expected {
+				t.Errorf("io.Writer: expected %s, got %s", tt.expected, buf.String())
+			}
+			if tt.wantErr == nil && tt.expected != "" {
+				if err := ValidateJSON(buf.Bytes()); err != nil {
+					t.Errorf("invalid output: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.BeginArray()
+	w.Int(1)
+	if err := w.Close(); err != ErrWriterState {
+		t.Errorf("incomplete document: got %v", err)
+	}
+	if buf.String() != "[1" {
+		t.Errorf("Close should flush, got %q", buf.String())
+	}
+	w.EndArray()
+	if err := w.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if buf.String() != "[1]" {
+		t.Errorf("expected [1], got %q", buf.String())
+	}
+}
+
+// failWriter fails every write
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriterError(t *testing.T) {
+	w := NewWriter(failWriter{})
+	w.BeginArray()
+	for i := 0; i < flushSize; i++ {
+		w.String(strings.Repeat("x", 10))
+	}
+	err := w.Int(1)
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected the io.Writer's error, got %v", err)
+	}
+	if err := w.EndArray(); err == nil || err.Error() != "disk full" {
+		t.Errorf("the error should stick, got %v", err)
+	}
+}
+
+func TestWriterMatchesMarshal(t *testing.T) {
+	var v interface{}
+	if err := Unmarshal(readFile(t, "example.json"), &v); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewAppendWriter(nil)
+	var write func(v interface{})
+	write = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			// Marshal sorts keys, do the same
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			w.BeginObject()
+			for _, k := range keys {
+				w.Key(k)
+				write(v[k])
+			}
+			w.EndObject()
+		case []interface{}:
+			w.BeginArray()
+			for _, e := range v {
+				write(e)
+			}
+			w.EndArray()
+		case string:
+			w.String(v)
+		case float64:
+			w.Float(v)
+		case bool:
+			w.Bool(v)
+		case nil:
+			w.Null()
+		}
+	}
+	write(v)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Marshal escapes html, the Writer doesn't
+	expected = bytes.ReplaceAll(expected, []byte(`\u0026`), []byte("&"))
+	expected = bytes.ReplaceAll(expected, []byte(`\u003c`), []byte("<"))
+	expected = bytes.ReplaceAll(expected, []byte(`\u003e`), []byte(">"))
+	if !bytes.Equal(w.Bytes(), expected) {
+		t.Errorf("expected\n%s\ngot\n%s", expected, w.Bytes())
+	}
+}